@@ -4,8 +4,7 @@ const createConfig = (): CodegenConfig => ({
 schema: ['../../apps/graphql-server/schema.graphql'],
 documents: null,
 generates: {
-  '__generated__/baseTypes.ts': {
-    plugins: ['typescript'],
+  '__generated__/': {
     preset: 'client',
   }
 }
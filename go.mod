@@ -2,32 +2,37 @@ module github.com/simse/faster-graphql-codegen
 
 go 1.23.1
 
+require (
+	github.com/bmatcuk/doublestar/v4 v4.6.1
+	github.com/briandowns/spinner v1.23.1
+	github.com/dop251/goja v0.0.0-20240828124009-016eb7256539
+	github.com/evanw/esbuild v0.23.1
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/gookit/color v1.5.4
+	github.com/iancoleman/strcase v0.3.0
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/vektah/gqlparser/v2 v2.5.16
+	gopkg.in/yaml.v3 v3.0.1
+)
+
 require (
 	github.com/VividCortex/ewma v1.2.0 // indirect
 	github.com/acarl005/stripansi v0.0.0-20180116102854-5a71ef0e047d // indirect
 	github.com/agnivade/levenshtein v1.1.1 // indirect
-	github.com/briandowns/spinner v1.23.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/dlclark/regexp2 v1.11.4 // indirect
-	github.com/dop251/goja v0.0.0-20240828124009-016eb7256539 // indirect
-	github.com/evanw/esbuild v0.23.1 // indirect
 	github.com/fatih/color v1.17.0 // indirect
 	github.com/go-sourcemap/sourcemap v2.1.3+incompatible // indirect
 	github.com/google/pprof v0.0.0-20230207041349-798e818bf904 // indirect
-	github.com/gookit/color v1.5.4 // indirect
-	github.com/iancoleman/strcase v0.3.0 // indirect
 	github.com/lmittmann/tint v1.0.5 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/stretchr/testify v1.9.0 // indirect
 	github.com/vbauerster/mpb/v8 v8.8.3 // indirect
-	github.com/vektah/gqlparser/v2 v2.5.16 // indirect
 	github.com/xo/terminfo v0.0.0-20210125001918-ca9a967f8778 // indirect
 	golang.org/x/sys v0.24.0 // indirect
 	golang.org/x/term v0.1.0 // indirect
 	golang.org/x/text v0.18.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
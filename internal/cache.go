@@ -0,0 +1,394 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"github.com/vektah/gqlparser/v2/ast"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+)
+
+/*
+cacheFormat is bumped whenever the layout of cachedSchema changes
+*/
+const cacheFormat = "2"
+
+/*
+SchemaCache stores parsed and validated schemas on disk, keyed by the HashSources of their sources
+*/
+type SchemaCache struct {
+	Dir string
+}
+
+/*
+NewSchemaCache creates a cache under node_modules/.cache in rootDir, entries written by other builds are removed.
+Builds are told apart by their version and buildFingerprint, a dev or latest build without a fingerprint could be
+any code, so it gets no cache and nil is returned.
+*/
+func NewSchemaCache(rootDir string) *SchemaCache {
+	return newSchemaCache(rootDir, Version, buildFingerprint())
+}
+
+func newSchemaCache(rootDir string, version string, fingerprint string) *SchemaCache {
+	if fingerprint == "" && (version == "dev" || version == "latest") {
+		return nil
+	}
+
+	cacheDir := filepath.Join(rootDir, "node_modules", ".cache", "faster-graphql-codegen")
+	versionDir := "v" + version + "-" + cacheFormat
+	if fingerprint != "" {
+		versionDir = "v" + version + "-" + fingerprint + "-" + cacheFormat
+	}
+
+	// entries of other builds can never be read again
+	entries, _ := os.ReadDir(cacheDir)
+	for _, entry := range entries {
+		if entry.Name() != versionDir {
+			_ = os.RemoveAll(filepath.Join(cacheDir, entry.Name()))
+		}
+	}
+
+	return &SchemaCache{
+		Dir: filepath.Join(cacheDir, versionDir),
+	}
+}
+
+/*
+buildFingerprint identifies the code of the running executable: the VCS revision it was built from, or a hash of the
+executable when it was built from modified sources or outside a repository. It is empty if neither is available.
+*/
+func buildFingerprint() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		var revision string
+		modified := false
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+
+		if revision != "" && !modified {
+			return revision[:min(len(revision), 12)]
+		}
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+
+	file, err := os.Open(executable)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+/*
+Get reads a schema from cache, any error is treated as a cache miss
+*/
+func (c *SchemaCache) Get(key string) (*ast.Schema, bool) {
+	file, err := os.Open(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	cached := cachedSchema{}
+	if err := gob.NewDecoder(file).Decode(&cached); err != nil {
+		return nil, false
+	}
+
+	return cached.toSchema(), true
+}
+
+/*
+Put writes a schema to cache, the file is renamed into place so concurrent readers never see a partial entry
+*/
+func (c *SchemaCache) Put(key string, schema *ast.Schema) error {
+	if err := os.MkdirAll(c.Dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	encodeErr := gob.NewEncoder(file).Encode(newCachedSchema(schema))
+	closeErr := file.Close()
+	if err := errors.Join(encodeErr, closeErr); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), c.path(key))
+}
+
+func (c *SchemaCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".gob")
+}
+
+/*
+cachedSchema is the serialized form of a schema. Definitions are copied without comments and positions, except
+for the position of each type, because every position would otherwise hold a copy of the entire source.
+*/
+type cachedSchema struct {
+	Description   string
+	Types         []*ast.Definition
+	Directives    []*ast.DirectiveDefinition
+	PossibleTypes map[string][]string
+	Implements    map[string][]string
+	Query         string
+	Mutation      string
+	Subscription  string
+}
+
+func newCachedSchema(schema *ast.Schema) cachedSchema {
+	cached := cachedSchema{
+		Description:   schema.Description,
+		PossibleTypes: definitionNames(schema.PossibleTypes),
+		Implements:    definitionNames(schema.Implements),
+	}
+
+	for _, definition := range schema.Types {
+		cached.Types = append(cached.Types, copyDefinition(definition))
+	}
+	slices.SortFunc(cached.Types, func(a, b *ast.Definition) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, directive := range schema.Directives {
+		cached.Directives = append(cached.Directives, &ast.DirectiveDefinition{
+			Description:  directive.Description,
+			Name:         directive.Name,
+			Arguments:    copyArguments(directive.Arguments),
+			Locations:    directive.Locations,
+			IsRepeatable: directive.IsRepeatable,
+			Position:     copyPosition(directive.Position),
+		})
+	}
+	slices.SortFunc(cached.Directives, func(a, b *ast.DirectiveDefinition) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	if schema.Query != nil {
+		cached.Query = schema.Query.Name
+	}
+	if schema.Mutation != nil {
+		cached.Mutation = schema.Mutation.Name
+	}
+	if schema.Subscription != nil {
+		cached.Subscription = schema.Subscription.Name
+	}
+
+	return cached
+}
+
+func (c *cachedSchema) toSchema() *ast.Schema {
+	schema := &ast.Schema{
+		Description:   c.Description,
+		Types:         make(map[string]*ast.Definition, len(c.Types)),
+		Directives:    make(map[string]*ast.DirectiveDefinition, len(c.Directives)),
+		PossibleTypes: make(map[string][]*ast.Definition, len(c.PossibleTypes)),
+		Implements:    make(map[string][]*ast.Definition, len(c.Implements)),
+	}
+
+	// sources are shared between definitions, like they are when parsed
+	sources := make(map[string]*ast.Source)
+	for _, definition := range c.Types {
+		if definition.Position != nil && definition.Position.Src != nil {
+			source, ok := sources[definition.Position.Src.Name]
+			if !ok {
+				source = definition.Position.Src
+				sources[source.Name] = source
+			}
+			definition.Position.Src = source
+		}
+
+		schema.Types[definition.Name] = definition
+	}
+
+	for _, directive := range c.Directives {
+		if directive.Position != nil {
+			if source, ok := sources[directive.Position.Src.Name]; ok {
+				directive.Position.Src = source
+			} else {
+				sources[directive.Position.Src.Name] = directive.Position.Src
+			}
+		}
+
+		schema.Directives[directive.Name] = directive
+	}
+
+	for name, possibleTypes := range c.PossibleTypes {
+		for _, possibleType := range possibleTypes {
+			schema.PossibleTypes[name] = append(schema.PossibleTypes[name], schema.Types[possibleType])
+		}
+	}
+
+	for name, implements := range c.Implements {
+		for _, implement := range implements {
+			schema.Implements[name] = append(schema.Implements[name], schema.Types[implement])
+		}
+	}
+
+	schema.Query = schema.Types[c.Query]
+	schema.Mutation = schema.Types[c.Mutation]
+	schema.Subscription = schema.Types[c.Subscription]
+
+	return schema
+}
+
+func definitionNames(definitions map[string][]*ast.Definition) map[string][]string {
+	names := make(map[string][]string, len(definitions))
+
+	for key, list := range definitions {
+		for _, definition := range list {
+			names[key] = append(names[key], definition.Name)
+		}
+	}
+
+	return names
+}
+
+func copyDefinition(definition *ast.Definition) *ast.Definition {
+	copied := &ast.Definition{
+		Kind:        definition.Kind,
+		Description: definition.Description,
+		Name:        definition.Name,
+		Directives:  copyDirectives(definition.Directives),
+		Interfaces:  definition.Interfaces,
+		Types:       definition.Types,
+		BuiltIn:     definition.BuiltIn,
+	}
+
+	copied.Position = copyPosition(definition.Position)
+
+	for _, field := range definition.Fields {
+		copied.Fields = append(copied.Fields, &ast.FieldDefinition{
+			Description:  field.Description,
+			Name:         field.Name,
+			Arguments:    copyArguments(field.Arguments),
+			DefaultValue: copyValue(field.DefaultValue),
+			Type:         copyType(field.Type),
+			Directives:   copyDirectives(field.Directives),
+		})
+	}
+
+	for _, enumValue := range definition.EnumValues {
+		copied.EnumValues = append(copied.EnumValues, &ast.EnumValueDefinition{
+			Description: enumValue.Description,
+			Name:        enumValue.Name,
+			Directives:  copyDirectives(enumValue.Directives),
+		})
+	}
+
+	return copied
+}
+
+/*
+copyPosition copies a position with a stub of its source, which only keeps the name
+*/
+func copyPosition(position *ast.Position) *ast.Position {
+	if position == nil || position.Src == nil {
+		return nil
+	}
+
+	return &ast.Position{
+		Start:  position.Start,
+		End:    position.End,
+		Line:   position.Line,
+		Column: position.Column,
+		Src: &ast.Source{
+			Name:    position.Src.Name,
+			BuiltIn: position.Src.BuiltIn,
+		},
+	}
+}
+
+func copyArguments(arguments ast.ArgumentDefinitionList) ast.ArgumentDefinitionList {
+	var copied ast.ArgumentDefinitionList
+
+	for _, argument := range arguments {
+		copied = append(copied, &ast.ArgumentDefinition{
+			Description:  argument.Description,
+			Name:         argument.Name,
+			DefaultValue: copyValue(argument.DefaultValue),
+			Type:         copyType(argument.Type),
+			Directives:   copyDirectives(argument.Directives),
+		})
+	}
+
+	return copied
+}
+
+func copyDirectives(directives ast.DirectiveList) ast.DirectiveList {
+	var copied ast.DirectiveList
+
+	for _, directive := range directives {
+		copiedDirective := &ast.Directive{
+			Name:     directive.Name,
+			Location: directive.Location,
+		}
+
+		for _, argument := range directive.Arguments {
+			copiedDirective.Arguments = append(copiedDirective.Arguments, &ast.Argument{
+				Name:  argument.Name,
+				Value: copyValue(argument.Value),
+			})
+		}
+
+		copied = append(copied, copiedDirective)
+	}
+
+	return copied
+}
+
+func copyValue(value *ast.Value) *ast.Value {
+	if value == nil {
+		return nil
+	}
+
+	copied := &ast.Value{
+		Raw:  value.Raw,
+		Kind: value.Kind,
+	}
+
+	for _, child := range value.Children {
+		copied.Children = append(copied.Children, &ast.ChildValue{
+			Name:  child.Name,
+			Value: copyValue(child.Value),
+		})
+	}
+
+	return copied
+}
+
+func copyType(fieldType *ast.Type) *ast.Type {
+	if fieldType == nil {
+		return nil
+	}
+
+	return &ast.Type{
+		NamedType: fieldType.NamedType,
+		Elem:      copyType(fieldType.Elem),
+		NonNull:   fieldType.NonNull,
+	}
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSchemaCache tests that a schema survives a round trip through the cache
+func TestSchemaCache(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"schema.graphql": `
+			"The root query"
+			type Query { node(id: ID!): Node, search(term: String = "all"): [SearchResult!]! }
+			interface Node { id: ID! }
+			type User implements Node { id: ID!, name: String @deprecated(reason: "Use id") }
+			type Post implements Node { id: ID! }
+			union SearchResult = User | Post
+			enum Role { ADMIN, GUEST }
+		`,
+	})
+
+	cache := NewSchemaCache(dir)
+	options := SchemaLoadOptions{Cache: cache}
+	schemaFile := filepath.Join(dir, "schema.graphql")
+
+	loadedSchema, err := LoadSchemaWithOptions(options, schemaFile)
+	if err != nil {
+		t.Fatalf("LoadSchemaWithOptions() error = %v", err)
+	}
+
+	entries, _ := os.ReadDir(cache.Dir)
+	if len(entries) != 1 {
+		t.Fatalf("cache contains %d entries, expected 1", len(entries))
+	}
+
+	cachedSchema, err := LoadSchemaWithOptions(options, schemaFile)
+	if err != nil {
+		t.Fatalf("LoadSchemaWithOptions() error = %v", err)
+	}
+
+	if cachedSchema == loadedSchema {
+		t.Fatalf("LoadSchemaWithOptions() did not read the schema from cache")
+	}
+
+	if len(cachedSchema.Types) != len(loadedSchema.Types) {
+		t.Errorf("cached schema has %d types, expected %d", len(cachedSchema.Types), len(loadedSchema.Types))
+	}
+
+	if cachedSchema.Query != cachedSchema.Types["Query"] || cachedSchema.Query.Description != "The root query" {
+		t.Errorf("cached schema has query type %v, expected Query", cachedSchema.Query)
+	}
+
+	search := cachedSchema.Query.Fields.ForName("search")
+	if search.Type.String() != "[SearchResult!]!" || search.Arguments.ForName("term").DefaultValue.Raw != "all" {
+		t.Errorf("cached schema did not keep Query.search")
+	}
+
+	if cachedSchema.Types["User"].Fields.ForName("name").Directives.ForName("deprecated") == nil {
+		t.Errorf("cached schema did not keep the directives of User.name")
+	}
+
+	possibleTypes := cachedSchema.GetPossibleTypes(cachedSchema.Types["SearchResult"])
+	if len(possibleTypes) != 2 || possibleTypes[0] != cachedSchema.Types["User"] {
+		t.Errorf("cached schema has possible types %v, expected User and Post", possibleTypes)
+	}
+
+	if cachedSchema.Types["User"].Position.Src.Name != schemaFile {
+		t.Errorf("cached schema did not keep the source of User")
+	}
+}
+
+func TestSchemaCacheRemovesOtherVersions(t *testing.T) {
+	dir := t.TempDir()
+	staleDir := filepath.Join(dir, "node_modules", ".cache", "faster-graphql-codegen", "v0.0.1-1")
+	if err := os.MkdirAll(staleDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	NewSchemaCache(dir)
+
+	if _, err := os.Stat(staleDir); !os.IsNotExist(err) {
+		t.Errorf("NewSchemaCache() did not remove the cache of another version")
+	}
+}
+
+// TestSchemaCacheBuilds tests that builds are cached apart, and that builds which cannot be told apart are not cached
+func TestSchemaCacheBuilds(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		fingerprint string
+		expected    string
+	}{
+		{name: "Release", version: "1.2.0", expected: "v1.2.0-" + cacheFormat},
+		{name: "ReleaseWithFingerprint", version: "1.2.0", fingerprint: "abc", expected: "v1.2.0-abc-" + cacheFormat},
+		{name: "Dev", version: "dev", fingerprint: "abc", expected: "vdev-abc-" + cacheFormat},
+		{name: "DevWithoutFingerprint", version: "dev"},
+		{name: "LatestWithoutFingerprint", version: "latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newSchemaCache(t.TempDir(), tt.version, tt.fingerprint)
+			if tt.expected == "" {
+				if cache != nil {
+					t.Errorf("newSchemaCache() = %v, expected no cache", cache.Dir)
+				}
+				return
+			}
+
+			if cache == nil || filepath.Base(cache.Dir) != tt.expected {
+				t.Errorf("newSchemaCache() = %+v, expected a cache in %s", cache, tt.expected)
+			}
+		})
+	}
+
+	if buildFingerprint() == "" {
+		t.Errorf("buildFingerprint() is empty, expected a hash of the test executable")
+	}
+}
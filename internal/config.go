@@ -9,19 +9,86 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"slices"
 	"strings"
 )
 
 type Config struct {
-	Schemas   []string             `yaml:"schema"`
-	Documents []string             `yaml:"documents"`
-	Overwrite bool                 `yaml:"overwrite"`
-	Generates map[string]Generates `yaml:"generates"`
+	Schemas []string `yaml:"-"`
+	// SchemaHeaders holds the headers given in the object form of a schema pointer, keyed by pointer
+	SchemaHeaders map[string]map[string]string `yaml:"-"`
+	Documents     []string                     `yaml:"-"`
+	Overwrite     *bool                        `yaml:"overwrite"`
+	Sort          *bool                        `yaml:"sort"`
+	Header        *Header                      `yaml:"header"`
+	// IgnoreValidationErrors generates from documents that do not validate against the schema, reporting the errors
+	IgnoreValidationErrors bool `yaml:"ignoreValidationErrors"`
+	// Config holds the plugin config shared by all generates entries
+	Config    map[string]interface{} `yaml:"config"`
+	Generates map[string]Generates   `yaml:"generates"`
 }
 
 type Generates struct {
-	Plugins []string `yaml:"plugins"`
-	Preset  string   `yaml:"preset"`
+	Plugins []string `yaml:"-"`
+	// PluginConfig holds the config given in the object form of a plugin, keyed by plugin name
+	PluginConfig map[string]map[string]interface{} `yaml:"-"`
+	Preset       string                            `yaml:"preset"`
+	// PresetConfig holds the options of the preset
+	PresetConfig map[string]interface{} `yaml:"presetConfig"`
+	Overwrite    *bool                  `yaml:"overwrite"`
+	Sort         *bool                  `yaml:"sort"`
+	Header       *Header                `yaml:"header"`
+	Config       map[string]interface{} `yaml:"config"`
+
+	// presetFile is set on the files a preset writes with the plugins only presets can use
+	presetFile bool
+}
+
+/*
+PluginConfig merges the config a plugin of a generates entry runs with. Like upstream, the config of the entry
+overrides the root config and the config of the plugin overrides both, keys are not merged any deeper.
+*/
+func (c Config) PluginConfig(generates Generates, plugin string) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for _, config := range []map[string]interface{}{c.Config, generates.Config, generates.PluginConfig[plugin]} {
+		for key, value := range config {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
+
+/*
+ShouldOverwrite checks if an existing output of a generates entry may be replaced, the entry overrides the root
+config and overwriting is on by default, like upstream
+*/
+func (c Config) ShouldOverwrite(generates Generates) bool {
+	if generates.Overwrite != nil {
+		return *generates.Overwrite
+	}
+
+	if c.Overwrite != nil {
+		return *c.Overwrite
+	}
+
+	return true
+}
+
+/*
+ShouldSort checks if the output of a generates entry should be sorted by name, the entry overrides the root config
+and sorting is on by default, like upstream
+*/
+func (c Config) ShouldSort(generates Generates) bool {
+	if generates.Sort != nil {
+		return *generates.Sort
+	}
+
+	if c.Sort != nil {
+		return *c.Sort
+	}
+
+	return true
 }
 
 func (p *Project) GetConfig() (Config, error) {
@@ -57,6 +124,109 @@ func ParseYAMLConfig(configData []byte) (Config, error) {
 	return parsedConfig, nil
 }
 
+/*
+UnmarshalYAML decodes a config, the schema and documents fields are decoded separately because they may be a single
+string, and schemas may mix strings and objects
+*/
+func (c *Config) UnmarshalYAML(value *yaml.Node) error {
+	type plainConfig Config
+
+	var schemaNode, documentsNode *yaml.Node
+	remainingNode := *value
+	remainingNode.Content = nil
+
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		switch value.Content[i].Value {
+		case "schema":
+			schemaNode = value.Content[i+1]
+		case "documents":
+			documentsNode = value.Content[i+1]
+		default:
+			remainingNode.Content = append(remainingNode.Content, value.Content[i], value.Content[i+1])
+		}
+	}
+
+	if err := remainingNode.Decode((*plainConfig)(c)); err != nil {
+		return err
+	}
+
+	if documentsNode != nil {
+		var documentsValue interface{}
+		if err := documentsNode.Decode(&documentsValue); err != nil {
+			return err
+		}
+
+		documents, err := getDocuments(documentsValue)
+		if err != nil {
+			return fmt.Errorf("error parsing 'documents': %v", err)
+		}
+		c.Documents = documents
+	}
+
+	if schemaNode == nil {
+		return nil
+	}
+
+	var schemaValue interface{}
+	if err := schemaNode.Decode(&schemaValue); err != nil {
+		return err
+	}
+
+	schemas, schemaHeaders, err := getSchemaPointers(schemaValue)
+	if err != nil {
+		return fmt.Errorf("error parsing 'schema': %v", err)
+	}
+
+	c.Schemas = schemas
+	c.SchemaHeaders = schemaHeaders
+
+	return nil
+}
+
+/*
+UnmarshalYAML decodes a generates entry, the plugins field is decoded separately because it may mix strings and
+objects
+*/
+func (g *Generates) UnmarshalYAML(value *yaml.Node) error {
+	type plainGenerates Generates
+
+	var pluginsNode *yaml.Node
+	remainingNode := *value
+	remainingNode.Content = nil
+
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if value.Content[i].Value == "plugins" {
+			pluginsNode = value.Content[i+1]
+			continue
+		}
+
+		remainingNode.Content = append(remainingNode.Content, value.Content[i], value.Content[i+1])
+	}
+
+	if err := remainingNode.Decode((*plainGenerates)(g)); err != nil {
+		return err
+	}
+
+	if pluginsNode == nil {
+		return nil
+	}
+
+	var pluginsValue interface{}
+	if err := pluginsNode.Decode(&pluginsValue); err != nil {
+		return err
+	}
+
+	plugins, pluginConfig, err := getPlugins(pluginsValue)
+	if err != nil {
+		return fmt.Errorf("error parsing 'plugins': %v", err)
+	}
+
+	g.Plugins = plugins
+	g.PluginConfig = pluginConfig
+
+	return nil
+}
+
 // Parse dynamic configs (JS and TS)
 
 /*
@@ -135,22 +305,68 @@ func executeJSConfigFile(input string) (Config, error) {
 
 	// Get 'schema' field
 	if schemaValue, ok := exportResult["schema"]; ok {
-		schemas, err := getStringOrStringSlice(schemaValue)
+		schemas, schemaHeaders, err := getSchemaPointers(schemaValue)
 		if err != nil {
 			return Config{}, fmt.Errorf("error parsing 'schema': %v", err)
 		}
 		config.Schemas = schemas
+		config.SchemaHeaders = schemaHeaders
 	} else {
 		return Config{}, fmt.Errorf("'schema' field is required")
 	}
 
+	// Get 'documents' field
+	if documentsValue, ok := exportResult["documents"]; ok {
+		documents, err := getDocuments(documentsValue)
+		if err != nil {
+			return Config{}, fmt.Errorf("error parsing 'documents': %v", err)
+		}
+		config.Documents = documents
+	}
+
+	// Get 'ignoreValidationErrors' field
+	if ignoreValue, ok := exportResult["ignoreValidationErrors"]; ok {
+		ignore, err := getBool(ignoreValue)
+		if err != nil {
+			return Config{}, fmt.Errorf("error parsing 'ignoreValidationErrors': %v", err)
+		}
+		config.IgnoreValidationErrors = ignore
+	}
+
 	// Get 'overwrite' field
 	if overwriteValue, ok := exportResult["overwrite"]; ok {
 		overwrite, err := getBool(overwriteValue)
 		if err != nil {
 			return Config{}, fmt.Errorf("error parsing 'overwrite': %v", err)
 		}
-		config.Overwrite = overwrite
+		config.Overwrite = &overwrite
+	}
+
+	// Get 'sort' field
+	if sortValue, ok := exportResult["sort"]; ok {
+		sort, err := getBool(sortValue)
+		if err != nil {
+			return Config{}, fmt.Errorf("error parsing 'sort': %v", err)
+		}
+		config.Sort = &sort
+	}
+
+	// Get 'header' field
+	if headerValue, ok := exportResult["header"]; ok {
+		header, err := getHeader(headerValue)
+		if err != nil {
+			return Config{}, fmt.Errorf("error parsing 'header': %v", err)
+		}
+		config.Header = &header
+	}
+
+	// Get 'config' field
+	if configValue, ok := exportResult["config"]; ok {
+		pluginConfig, err := getMapStringInterface(configValue)
+		if err != nil {
+			return Config{}, fmt.Errorf("error parsing 'config': %v", err)
+		}
+		config.Config = pluginConfig
 	}
 
 	// Get 'generates' field
@@ -169,11 +385,60 @@ func executeJSConfigFile(input string) (Config, error) {
 			generate := Generates{}
 
 			if pluginsValue, ok := destConfigMap["plugins"]; ok {
-				pluginsSlice, err := getStringSlice(pluginsValue)
+				plugins, pluginConfig, err := getPlugins(pluginsValue)
 				if err != nil {
 					return Config{}, fmt.Errorf("error parsing 'plugins' in 'generates[%s]': %v", destination, err)
 				}
-				generate.Plugins = pluginsSlice
+				generate.Plugins = plugins
+				generate.PluginConfig = pluginConfig
+			}
+
+			if presetValue, ok := destConfigMap["preset"]; ok {
+				preset, err := getString(presetValue)
+				if err != nil {
+					return Config{}, fmt.Errorf("error parsing 'preset' in 'generates[%s]': %v", destination, err)
+				}
+				generate.Preset = preset
+			}
+
+			if presetConfigValue, ok := destConfigMap["presetConfig"]; ok {
+				presetConfig, err := getMapStringInterface(presetConfigValue)
+				if err != nil {
+					return Config{}, fmt.Errorf("error parsing 'presetConfig' in 'generates[%s]': %v", destination, err)
+				}
+				generate.PresetConfig = presetConfig
+			}
+
+			if overwriteValue, ok := destConfigMap["overwrite"]; ok {
+				overwrite, err := getBool(overwriteValue)
+				if err != nil {
+					return Config{}, fmt.Errorf("error parsing 'overwrite' in 'generates[%s]': %v", destination, err)
+				}
+				generate.Overwrite = &overwrite
+			}
+
+			if sortValue, ok := destConfigMap["sort"]; ok {
+				sort, err := getBool(sortValue)
+				if err != nil {
+					return Config{}, fmt.Errorf("error parsing 'sort' in 'generates[%s]': %v", destination, err)
+				}
+				generate.Sort = &sort
+			}
+
+			if headerValue, ok := destConfigMap["header"]; ok {
+				header, err := getHeader(headerValue)
+				if err != nil {
+					return Config{}, fmt.Errorf("error parsing 'header' in 'generates[%s]': %v", destination, err)
+				}
+				generate.Header = &header
+			}
+
+			if configValue, ok := destConfigMap["config"]; ok {
+				pluginConfig, err := getMapStringInterface(configValue)
+				if err != nil {
+					return Config{}, fmt.Errorf("error parsing 'config' in 'generates[%s]': %v", destination, err)
+				}
+				generate.Config = pluginConfig
 			}
 
 			config.Generates[destination] = generate
@@ -195,6 +460,155 @@ func getStringOrStringSlice(value interface{}) ([]string, error) {
 	}
 }
 
+// Helper function to get schema pointers, given as strings or as objects keyed by pointer with options such as headers
+func getSchemaPointers(value interface{}) ([]string, map[string]map[string]string, error) {
+	var schemas []string
+	var schemaHeaders map[string]map[string]string
+
+	addPointerObject := func(pointerObject map[string]interface{}) error {
+		// sort pointers, so the order does not depend on map iteration
+		pointers := make([]string, 0, len(pointerObject))
+		for pointer := range pointerObject {
+			pointers = append(pointers, pointer)
+		}
+		slices.Sort(pointers)
+
+		for _, pointer := range pointers {
+			schemas = append(schemas, pointer)
+
+			if pointerObject[pointer] == nil {
+				continue
+			}
+
+			options, err := getMapStringInterface(pointerObject[pointer])
+			if err != nil {
+				return fmt.Errorf("options of %s: %v", pointer, err)
+			}
+
+			if headersValue, ok := options["headers"]; ok {
+				headers, err := getStringMap(headersValue)
+				if err != nil {
+					return fmt.Errorf("headers of %s: %v", pointer, err)
+				}
+
+				if schemaHeaders == nil {
+					schemaHeaders = make(map[string]map[string]string)
+				}
+				schemaHeaders[pointer] = headers
+			}
+		}
+
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		schemas = append(schemas, v)
+	case map[string]interface{}:
+		if err := addPointerObject(v); err != nil {
+			return nil, nil, err
+		}
+	case []interface{}:
+		for i, elem := range v {
+			switch e := elem.(type) {
+			case string:
+				schemas = append(schemas, e)
+			case map[string]interface{}:
+				if err := addPointerObject(e); err != nil {
+					return nil, nil, err
+				}
+			default:
+				return nil, nil, fmt.Errorf("element at index %d is not a string or object", i)
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("value is not a string, object or array")
+	}
+
+	return schemas, schemaHeaders, nil
+}
+
+// Helper function to get document patterns, null is allowed for configs without documents
+func getDocuments(value interface{}) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	return getStringOrStringSlice(value)
+}
+
+// Helper function to get plugins, given as names or as objects with the name of the plugin as the only key and its
+// config as the value
+func getPlugins(value interface{}) ([]string, map[string]map[string]interface{}, error) {
+	elems, ok := value.([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("value is not an array")
+	}
+
+	plugins := make([]string, 0, len(elems))
+	var pluginConfig map[string]map[string]interface{}
+
+	for i, elem := range elems {
+		switch e := elem.(type) {
+		case string:
+			plugins = append(plugins, e)
+		case map[string]interface{}:
+			if len(e) != 1 {
+				return nil, nil, fmt.Errorf("element at index %d must have exactly one plugin name as key", i)
+			}
+
+			for name, configValue := range e {
+				plugins = append(plugins, name)
+
+				if configValue == nil {
+					continue
+				}
+
+				config, err := getMapStringInterface(configValue)
+				if err != nil {
+					return nil, nil, fmt.Errorf("config of %s: %v", name, err)
+				}
+
+				if pluginConfig == nil {
+					pluginConfig = make(map[string]map[string]interface{})
+				}
+				pluginConfig[name] = config
+			}
+		default:
+			return nil, nil, fmt.Errorf("element at index %d is not a string or object", i)
+		}
+	}
+
+	return plugins, pluginConfig, nil
+}
+
+// Helper function to get map[string]string
+func getStringMap(value interface{}) (map[string]string, error) {
+	m, err := getMapStringInterface(value)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(m))
+	for key, elem := range m {
+		str, ok := elem.(string)
+		if !ok {
+			return nil, fmt.Errorf("value of %s is not a string", key)
+		}
+		result[key] = str
+	}
+
+	return result, nil
+}
+
+// Helper function to get a string value
+func getString(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return "", fmt.Errorf("value is not a string")
+}
+
 // Helper function to get a boolean value
 func getBool(value interface{}) (bool, error) {
 	if b, ok := value.(bool); ok {
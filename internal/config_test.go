@@ -5,6 +5,10 @@ import (
 	"testing"
 )
 
+func boolPointer(value bool) *bool {
+	return &value
+}
+
 // Assuming your main code is in a file named `config.go`
 // We'll write the tests in `config_test.go`
 
@@ -32,7 +36,7 @@ func TestExecuteJSConfigFile(t *testing.T) {
             `,
 			expected: Config{
 				Schemas:   []string{"schema.graphql"},
-				Overwrite: true,
+				Overwrite: boolPointer(true),
 				Generates: map[string]Generates{
 					"output.ts": {
 						Plugins: []string{"typescript"},
@@ -57,7 +61,7 @@ func TestExecuteJSConfigFile(t *testing.T) {
             `,
 			expected: Config{
 				Schemas:   []string{"schema1.graphql", "schema2.graphql"},
-				Overwrite: false,
+				Overwrite: boolPointer(false),
 				Generates: map[string]Generates{
 					"output.ts": {
 						Plugins: []string{"typescript", "graphql-codegen"},
@@ -66,6 +70,155 @@ func TestExecuteJSConfigFile(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "ValidConfigWithSchemaHeaders",
+			input: `
+            var config = {
+                schema: [{ "https://api.example.com/graphql": { headers: { "X-Api-Key": "secret" } } }],
+                generates: {
+                    "output.ts": {
+                        plugins: ["typescript"]
+                    }
+                }
+            };
+            module.exports = { default: config };
+            `,
+			expected: Config{
+				Schemas: []string{"https://api.example.com/graphql"},
+				SchemaHeaders: map[string]map[string]string{
+					"https://api.example.com/graphql": {"X-Api-Key": "secret"},
+				},
+				Generates: map[string]Generates{
+					"output.ts": {
+						Plugins: []string{"typescript"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ValidConfigWithOutputOverwrite",
+			input: `
+            var config = {
+                schema: "schema.graphql",
+                overwrite: false,
+                generates: {
+                    "output.ts": {
+                        plugins: ["typescript"],
+                        overwrite: true
+                    }
+                }
+            };
+            module.exports = { default: config };
+            `,
+			expected: Config{
+				Schemas:   []string{"schema.graphql"},
+				Overwrite: boolPointer(false),
+				Generates: map[string]Generates{
+					"output.ts": {
+						Plugins:   []string{"typescript"},
+						Overwrite: boolPointer(true),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ValidConfigWithPluginConfig",
+			input: `
+            var config = {
+                schema: "schema.graphql",
+                config: { minify: false },
+                generates: {
+                    "schema.json": {
+                        plugins: [{ introspection: { minify: true } }, "schema-ast"],
+                        config: { descriptions: false }
+                    }
+                }
+            };
+            module.exports = { default: config };
+            `,
+			expected: Config{
+				Schemas: []string{"schema.graphql"},
+				Config:  map[string]interface{}{"minify": false},
+				Generates: map[string]Generates{
+					"schema.json": {
+						Plugins: []string{"introspection", "schema-ast"},
+						PluginConfig: map[string]map[string]interface{}{
+							"introspection": {"minify": true},
+						},
+						Config: map[string]interface{}{"descriptions": false},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ValidConfigWithDocuments",
+			input: `
+            var config = {
+                schema: "schema.graphql",
+                documents: "src/**/*.tsx",
+                generates: {
+                    "output.ts": {
+                        plugins: ["typescript-operations"]
+                    }
+                }
+            };
+            module.exports = { default: config };
+            `,
+			expected: Config{
+				Schemas:   []string{"schema.graphql"},
+				Documents: []string{"src/**/*.tsx"},
+				Generates: map[string]Generates{
+					"output.ts": {
+						Plugins: []string{"typescript-operations"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "InvalidPluginConfigType",
+			input: `
+            var config = {
+                schema: "schema.graphql",
+                generates: {
+                    "output.ts": {
+                        plugins: [{ typescript: "strict" }]
+                    }
+                }
+            };
+            module.exports = { default: config };
+            `,
+			expected: Config{},
+			wantErr:  true,
+		},
+		{
+			name: "ValidConfigWithPreset",
+			input: `
+            var config = {
+                schema: "schema.graphql",
+                generates: {
+                    "src/gql/": {
+                        preset: "client",
+                        presetConfig: { gqlTagName: "gql" }
+                    }
+                }
+            };
+            module.exports = { default: config };
+            `,
+			expected: Config{
+				Schemas: []string{"schema.graphql"},
+				Generates: map[string]Generates{
+					"src/gql/": {
+						Preset:       "client",
+						PresetConfig: map[string]interface{}{"gqlTagName": "gql"},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "MissingSchemaField",
 			input: `
@@ -369,3 +522,197 @@ func TestConvertInterfaceSliceToStringSlice(t *testing.T) {
 		})
 	}
 }
+
+// TestParseYAMLConfigSchema tests the different forms of the schema field in YAML configs
+func TestParseYAMLConfigSchema(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		expectedSchemas []string
+		expectedHeaders map[string]map[string]string
+		wantErr         bool
+	}{
+		{
+			name:            "String",
+			input:           `schema: schema.graphql`,
+			expectedSchemas: []string{"schema.graphql"},
+		},
+		{
+			name:            "StringSlice",
+			input:           `schema: [base.graphql, search.graphql]`,
+			expectedSchemas: []string{"base.graphql", "search.graphql"},
+		},
+		{
+			name: "URLWithHeaders",
+			input: `
+schema:
+  - https://api.example.com/graphql:
+      headers:
+        Authorization: Bearer token
+  - local.graphql
+`,
+			expectedSchemas: []string{"https://api.example.com/graphql", "local.graphql"},
+			expectedHeaders: map[string]map[string]string{
+				"https://api.example.com/graphql": {"Authorization": "Bearer token"},
+			},
+		},
+		{
+			name: "InvalidHeaders",
+			input: `
+schema:
+  https://api.example.com/graphql:
+    headers: [Authorization]
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseYAMLConfig([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseYAMLConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(result.Schemas, tt.expectedSchemas) {
+				t.Errorf("ParseYAMLConfig() schemas = %v, expected %v", result.Schemas, tt.expectedSchemas)
+			}
+			if !reflect.DeepEqual(result.SchemaHeaders, tt.expectedHeaders) {
+				t.Errorf("ParseYAMLConfig() headers = %v, expected %v", result.SchemaHeaders, tt.expectedHeaders)
+			}
+		})
+	}
+}
+
+// TestParseYAMLConfigPlugins tests the string and object forms of plugins in YAML configs
+func TestParseYAMLConfigPlugins(t *testing.T) {
+	tests := []struct {
+		name                 string
+		input                string
+		expectedPlugins      []string
+		expectedPluginConfig map[string]map[string]interface{}
+		wantErr              bool
+	}{
+		{
+			name: "Strings",
+			input: `
+generates:
+  types.ts:
+    plugins: [typescript]
+`,
+			expectedPlugins: []string{"typescript"},
+		},
+		{
+			name: "Objects",
+			input: `
+generates:
+  types.ts:
+    plugins:
+      - typescript:
+          enumsAsTypes: true
+      - typescript-operations:
+      - add
+`,
+			expectedPlugins: []string{"typescript", "typescript-operations", "add"},
+			expectedPluginConfig: map[string]map[string]interface{}{
+				"typescript": {"enumsAsTypes": true},
+			},
+		},
+		{
+			name: "MultipleKeys",
+			input: `
+generates:
+  types.ts:
+    plugins:
+      - typescript: {}
+        add: {}
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseYAMLConfig([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseYAMLConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			generates := result.Generates["types.ts"]
+			if !reflect.DeepEqual(generates.Plugins, tt.expectedPlugins) {
+				t.Errorf("ParseYAMLConfig() plugins = %v, expected %v", generates.Plugins, tt.expectedPlugins)
+			}
+			if !reflect.DeepEqual(generates.PluginConfig, tt.expectedPluginConfig) {
+				t.Errorf("ParseYAMLConfig() plugin config = %v, expected %v", generates.PluginConfig, tt.expectedPluginConfig)
+			}
+		})
+	}
+}
+
+// TestPluginConfig tests that plugin config overrides output config, which overrides root config
+func TestPluginConfig(t *testing.T) {
+	config, err := ParseYAMLConfig([]byte(`
+config:
+  scalars: root
+  enumsAsTypes: true
+  skipTypename: true
+generates:
+  types.ts:
+    config:
+      enumsAsTypes: false
+      avoidOptionals: true
+    plugins:
+      - typescript:
+          avoidOptionals: false
+      - typescript-operations
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generates := config.Generates["types.ts"]
+
+	expected := map[string]interface{}{
+		"scalars":        "root",
+		"enumsAsTypes":   false,
+		"skipTypename":   true,
+		"avoidOptionals": false,
+	}
+	if merged := config.PluginConfig(generates, "typescript"); !reflect.DeepEqual(merged, expected) {
+		t.Errorf("PluginConfig() = %v, expected %v", merged, expected)
+	}
+
+	expected["avoidOptionals"] = true
+	if merged := config.PluginConfig(generates, "typescript-operations"); !reflect.DeepEqual(merged, expected) {
+		t.Errorf("PluginConfig() = %v, expected %v", merged, expected)
+	}
+}
+
+// TestParseYAMLConfigDocuments tests the different forms of the documents field in YAML configs
+func TestParseYAMLConfigDocuments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`documents: src/**/*.graphql`, []string{"src/**/*.graphql"}},
+		{`documents: [src/**/*.graphql, "!src/ignored.graphql"]`, []string{"src/**/*.graphql", "!src/ignored.graphql"}},
+		{`documents: null`, nil},
+	}
+
+	for _, tt := range tests {
+		result, err := ParseYAMLConfig([]byte("schema: schema.graphql\n" + tt.input))
+		if err != nil {
+			t.Fatalf("ParseYAMLConfig(%q) error = %v", tt.input, err)
+		}
+
+		if !reflect.DeepEqual(result.Documents, tt.expected) {
+			t.Errorf("ParseYAMLConfig(%q) documents = %v, expected %v", tt.input, result.Documents, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"strconv"
+	"strings"
+)
+
+/*
+Diagnostic is an error located in a source file, it can render the offending line like a compiler would
+*/
+type Diagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+	// Rule names the gqlparser validation rule, or the stage that failed if no rule applies
+	Rule   string
+	Source *ast.Source
+}
+
+func (d *Diagnostic) Error() string {
+	location := d.File
+	if d.Line > 0 {
+		location += ":" + strconv.Itoa(d.Line) + ":" + strconv.Itoa(d.Column)
+	}
+
+	message := location + ": " + d.Message
+	if d.Rule != "" {
+		message += " (" + d.Rule + ")"
+	}
+
+	return message
+}
+
+/*
+NewDiagnostic converts a gqlparser error to a diagnostic, sources are searched for the file named by the error
+*/
+func NewDiagnostic(err *gqlerror.Error, rule string, sources []*ast.Source) *Diagnostic {
+	diagnostic := &Diagnostic{
+		Message: err.Message,
+		Rule:    rule,
+	}
+
+	if err.Rule != "" {
+		diagnostic.Rule = err.Rule
+	}
+
+	if file, ok := err.Extensions["file"].(string); ok {
+		diagnostic.File = file
+	}
+
+	if len(err.Locations) > 0 {
+		diagnostic.Line = err.Locations[0].Line
+		diagnostic.Column = err.Locations[0].Column
+	}
+
+	for _, source := range sources {
+		if source.Name == diagnostic.File {
+			diagnostic.Source = source
+		}
+	}
+
+	return diagnostic
+}
+
+/*
+toDiagnostic wraps err in a diagnostic if it is a gqlparser error, other errors are returned unchanged
+*/
+func toDiagnostic(err error, rule string, sources []*ast.Source) error {
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) {
+		return NewDiagnostic(gqlErr, rule, sources)
+	}
+
+	return err
+}
+
+/*
+CodeFrame renders the lines around the diagnostic with a caret under the offending column
+*/
+func (d *Diagnostic) CodeFrame() string {
+	if d.Source == nil || d.Line <= 0 {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSuffix(d.Source.Input, "\n"), "\n")
+	if d.Line > len(lines) {
+		return ""
+	}
+
+	firstLine := max(d.Line-2, 1)
+	lastLine := min(d.Line+2, len(lines))
+	gutterWidth := len(strconv.Itoa(lastLine))
+
+	frame := strings.Builder{}
+	for lineNumber := firstLine; lineNumber <= lastLine; lineNumber++ {
+		marker := " "
+		if lineNumber == d.Line {
+			marker = ">"
+		}
+
+		line := strings.TrimRight(lines[lineNumber-1], "\r")
+		frame.WriteString(fmt.Sprintf("%s %*d | %s\n", marker, gutterWidth, lineNumber, line))
+
+		if lineNumber == d.Line {
+			frame.WriteString(fmt.Sprintf("  %*s | %s^\n", gutterWidth, "", caretPadding(line, d.Column)))
+		}
+	}
+
+	return frame.String()
+}
+
+/*
+caretPadding creates whitespace reaching the given column, tabs are kept so the caret lines up
+*/
+func caretPadding(line string, column int) string {
+	padding := strings.Builder{}
+
+	for i, character := range []rune(line) {
+		if i >= column-1 {
+			break
+		}
+
+		if character == '\t' {
+			padding.WriteRune('\t')
+		} else {
+			padding.WriteRune(' ')
+		}
+	}
+
+	return padding.String()
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestSchemaDiagnostic tests that schema errors point at the offending file, line and column
+func TestSchemaDiagnostic(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"base.graphql":   "type Query {\n  hello: String\n}\n",
+		"broken.graphql": "type User {\n  id: ID!\n  name: Strin\n}\n",
+	})
+
+	_, err := LoadSchema(filepath.Join(dir, "base.graphql"), filepath.Join(dir, "broken.graphql"))
+
+	var diagnostic *Diagnostic
+	if !errors.As(err, &diagnostic) {
+		t.Fatalf("LoadSchema() error = %v, expected a diagnostic", err)
+	}
+
+	if diagnostic.File != filepath.Join(dir, "broken.graphql") || diagnostic.Line != 3 || diagnostic.Column != 9 {
+		t.Errorf("diagnostic is at %s:%d:%d, expected broken.graphql:3:9", diagnostic.File, diagnostic.Line, diagnostic.Column)
+	}
+
+	if diagnostic.Rule != "SchemaValidation" {
+		t.Errorf("diagnostic rule = %s, expected SchemaValidation", diagnostic.Rule)
+	}
+
+	expectedFrame := "  1 | type User {\n" +
+		"  2 |   id: ID!\n" +
+		"> 3 |   name: Strin\n" +
+		"    |         ^\n" +
+		"  4 | }\n"
+
+	if diagnostic.CodeFrame() != expectedFrame {
+		t.Errorf("CodeFrame() = \n%s\nexpected\n%s", diagnostic.CodeFrame(), expectedFrame)
+	}
+}
+
+func TestSyntaxDiagnostic(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"broken.graphql": "type Query {\n  hello String\n}\n",
+	})
+
+	_, err := LoadSchema(filepath.Join(dir, "broken.graphql"))
+
+	var diagnostic *Diagnostic
+	if !errors.As(err, &diagnostic) {
+		t.Fatalf("LoadSchema() error = %v, expected a diagnostic", err)
+	}
+
+	if diagnostic.Rule != "Syntax" || diagnostic.Line != 2 {
+		t.Errorf("diagnostic = %v, expected a syntax error on line 2", diagnostic)
+	}
+}
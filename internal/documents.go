@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"github.com/simse/faster-graphql-codegen/internal/plugins"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+/*
+codeExtensions are the extensions of files GraphQL documents are extracted from, other files are read as GraphQL
+*/
+var codeExtensions = []string{".ts", ".tsx", ".js", ".jsx", ".mts", ".cts", ".mjs", ".cjs"}
+
+/*
+LoadDocuments reads and parses the documents matched by the document patterns of a config. GraphQL files are
+documents on their own, JavaScript and Typescript files give a document per GraphQL template literal. Positions of
+the parsed documents point into the files they were read from.
+*/
+func LoadDocuments(rootDir string, patterns []string) ([]*plugins.Document, error) {
+	files, err := ExpandGlobs(rootDir, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []*plugins.Document
+	for _, file := range files {
+		dat, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		fileDocuments, err := parseDocumentFile(file, string(dat))
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, fileDocuments...)
+	}
+
+	return documents, nil
+}
+
+func parseDocumentFile(file string, content string) ([]*plugins.Document, error) {
+	if !slices.Contains(codeExtensions, strings.ToLower(filepath.Ext(file))) {
+		document, err := parseDocument(file, content, content, content)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*plugins.Document{document}, nil
+	}
+
+	var documents []*plugins.Document
+	for _, extracted := range ExtractDocuments(content) {
+		// the code before the document is blanked out, so the positions of the parsed document match the file
+		input := blankOut(content[:extracted.Offset]) + extracted.Query
+
+		document, err := parseDocument(file, input, extracted.Raw, content)
+		if err != nil {
+			return nil, err
+		}
+
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+func parseDocument(file string, input string, raw string, content string) (*plugins.Document, error) {
+	source := &ast.Source{Name: file, Input: input}
+
+	document, parseErr := parser.ParseQuery(source)
+	if parseErr != nil {
+		return nil, toDiagnostic(parseErr, "Syntax", []*ast.Source{{Name: file, Input: content}})
+	}
+
+	return &plugins.Document{
+		File:     file,
+		Source:   source,
+		Raw:      raw,
+		Document: document,
+	}, nil
+}
+
+/*
+blankOut replaces everything but line breaks with spaces
+*/
+func blankOut(content string) string {
+	blanked := []byte(content)
+	for i, character := range blanked {
+		if character != '\n' && character != '\r' {
+			blanked[i] = ' '
+		}
+	}
+
+	return string(blanked)
+}
+
+/*
+ValidateDocuments validates the documents of a project against its schema, every error becomes a task error with a
+diagnostic in the original file of the document
+*/
+func ValidateDocuments(project Project, schema *ast.Schema, documents []*plugins.Document) []*TaskError {
+	validationErrors := plugins.ValidateDocuments(schema, documents)
+	if len(validationErrors) == 0 {
+		return nil
+	}
+
+	sources := documentSources(documents)
+
+	taskErrors := make([]*TaskError, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		taskErrors = append(taskErrors, &TaskError{
+			ProjectRoot: project.RootDir,
+			ConfigFile:  project.ConfigFile,
+			Documents:   true,
+			Err:         NewDiagnostic(validationError, "DocumentValidation", sources),
+		})
+	}
+
+	return taskErrors
+}
+
+/*
+documentSources reads the files of documents, so diagnostics can show the offending lines
+*/
+func documentSources(documents []*plugins.Document) []*ast.Source {
+	var sources []*ast.Source
+	for _, document := range documents {
+		if slices.ContainsFunc(sources, func(source *ast.Source) bool { return source.Name == document.File }) {
+			continue
+		}
+
+		content, err := os.ReadFile(document.File)
+		if err != nil {
+			continue
+		}
+
+		sources = append(sources, &ast.Source{Name: document.File, Input: string(content)})
+	}
+
+	return sources
+}
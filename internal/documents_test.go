@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadDocuments tests loading documents from GraphQL and Typescript files with positions in the original files
+func TestLoadDocuments(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"src/queries.graphql": "query A { a }\n\nfragment F on Query { a }",
+		"src/App.tsx": "import { gql } from '@apollo/client';\n\nexport const B = gql`\n  query B {\n    b\n  }\n`;\n\n" +
+			"export const C = graphql(`query C { c }`);\n",
+		"src/ignored.graphql": "query Ignored { a }",
+	})
+
+	documents, err := LoadDocuments(dir, []string{"src/**/*.{graphql,tsx}", "!src/ignored.graphql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(documents) != 3 {
+		t.Fatalf("LoadDocuments() = %d documents, expected 3", len(documents))
+	}
+
+	tsxDocument := documents[0]
+	if tsxDocument.File != filepath.Join(dir, "src", "App.tsx") || tsxDocument.Raw != "\n  query B {\n    b\n  }\n" {
+		t.Errorf("LoadDocuments() first document = %s %q, expected query B of App.tsx", tsxDocument.File, tsxDocument.Raw)
+	}
+
+	position := tsxDocument.Document.Operations[0].SelectionSet[0].GetPosition()
+	if position.Line != 5 || position.Column != 5 {
+		t.Errorf("field b is at %d:%d, expected 5:5", position.Line, position.Column)
+	}
+
+	if position := documents[1].Document.Operations[0].Position; position.Line != 9 || position.Column != 27 {
+		t.Errorf("query C is at %d:%d, expected 9:27", position.Line, position.Column)
+	}
+
+	if len(documents[2].Document.Operations) != 1 || len(documents[2].Document.Fragments) != 1 {
+		t.Errorf("LoadDocuments() did not parse queries.graphql as one document")
+	}
+}
+
+// TestLoadDocumentsSyntaxError tests that syntax errors in extracted documents point at the original file
+func TestLoadDocumentsSyntaxError(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"App.tsx": "const a = 1;\nconst B = gql`\n  query B {\n    b(\n  }\n`;\n",
+	})
+
+	_, err := LoadDocuments(dir, []string{"*.tsx"})
+
+	var diagnostic *Diagnostic
+	if !errors.As(err, &diagnostic) {
+		t.Fatalf("LoadDocuments() error = %v, expected a diagnostic", err)
+	}
+	if diagnostic.Line != 5 || diagnostic.Source == nil || diagnostic.CodeFrame() == "" {
+		t.Errorf("LoadDocuments() diagnostic = %v, expected it on line 5 of App.tsx", diagnostic)
+	}
+}
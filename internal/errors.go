@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+/*
+TaskError describes a failure while loading a schema or generating a destination of a project
+*/
+type TaskError struct {
+	ProjectRoot string
+	ConfigFile  string
+	// Destination is empty when the failure is not specific to one output, e.g. when loading a schema
+	Destination string
+	// Plugin is empty when the failure is not caused by a plugin
+	Plugin string
+	// Documents is set when the failure is in the documents of the project, e.g. when they do not validate
+	Documents bool
+	Err       error
+}
+
+func (t *TaskError) Error() string {
+	message := filepath.Join(t.ProjectRoot, t.ConfigFile)
+
+	if t.Destination != "" {
+		message += " → " + t.Destination
+	}
+
+	if t.Plugin != "" {
+		message += " [" + t.Plugin + "]"
+	}
+
+	return message + ": " + t.Err.Error()
+}
+
+func (t *TaskError) Unwrap() error {
+	return t.Err
+}
+
+/*
+PluginError is returned by ExecuteDestinationTasks when a plugin fails
+*/
+type PluginError struct {
+	Plugin string
+	Err    error
+}
+
+func (p *PluginError) Error() string {
+	return p.Plugin + ": " + p.Err.Error()
+}
+
+func (p *PluginError) Unwrap() error {
+	return p.Err
+}
+
+/*
+taskErrorCollector gathers errors from concurrently running tasks
+*/
+type taskErrorCollector struct {
+	mu     sync.Mutex
+	errors []*TaskError
+}
+
+func (c *taskErrorCollector) Add(taskError *TaskError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errors = append(c.errors, taskError)
+}
+
+/*
+Errors returns the collected errors sorted by project and destination, so output does not depend on scheduling
+*/
+func (c *taskErrorCollector) Errors() []*TaskError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// errors of the same task keep the order they were found in
+	sorted := slices.Clone(c.errors)
+	slices.SortStableFunc(sorted, func(a, b *TaskError) int {
+		if order := strings.Compare(filepath.Join(a.ProjectRoot, a.ConfigFile), filepath.Join(b.ProjectRoot, b.ConfigFile)); order != 0 {
+			return order
+		}
+
+		return strings.Compare(a.Destination, b.Destination)
+	})
+
+	return sorted
+}
@@ -0,0 +1,262 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+)
+
+/*
+ExtractedDocument is a GraphQL document found in a JavaScript or Typescript file
+*/
+type ExtractedDocument struct {
+	// Raw is the content of the template literal as written
+	Raw string
+	// Query is Raw with interpolations blanked out, so it can be parsed
+	Query string
+	// Offset is the byte offset of the content in the file
+	Offset int
+}
+
+/*
+gqlIdentifiers are the tags and functions whose template literals hold GraphQL, like graphql-tag-pluck looks for
+*/
+var gqlIdentifiers = []string{"gql", "graphql"}
+
+/*
+regexKeywords are keywords a regular expression literal may follow, after any other word a slash divides
+*/
+var regexKeywords = []string{"return", "typeof", "case", "do", "else", "in", "of", "new", "delete", "void", "throw", "yield", "await"}
+
+/*
+ExtractDocuments finds the GraphQL documents of a JavaScript or Typescript file: template literals tagged with gql or
+graphql, passed to a gql or graphql function, or marked with a GraphQL comment. The file is scanned rather than
+parsed, so code that merely looks like these is found too.
+
+Regular expressions are told from division by the token before the slash, like the scanner of a parser would without
+the grammar. A regular expression right after the parenthesis of an if, for or while, or after the brace of a block,
+is taken as division, so a backtick in it starts a template literal. Tags that are renamed on import, such as
+import { gql as g }, are not recognized.
+*/
+func ExtractDocuments(content string) []ExtractedDocument {
+	s := &templateScanner{content: content}
+	s.scan(false)
+
+	return s.documents
+}
+
+type templateScanner struct {
+	content   string
+	i         int
+	documents []ExtractedDocument
+
+	// the tokens before the current position, to tell what a template literal or slash belongs to
+	lastWord       string
+	lastPunct      byte
+	wordBeforeCall string
+	magicComment   bool
+}
+
+/*
+scan reads code up to the end of the file, or up to the closing brace of an interpolation if nested is set
+*/
+func (s *templateScanner) scan(nested bool) {
+	depth := 0
+
+	for s.i < len(s.content) {
+		character := s.content[s.i]
+
+		switch {
+		case character == '/' && s.peek(1) == '/':
+			s.skipUntil("\n")
+		case character == '/' && s.peek(1) == '*':
+			end := strings.Index(s.content[s.i+2:], "*/")
+			if end == -1 {
+				s.i = len(s.content)
+				return
+			}
+
+			comment := s.content[s.i+2 : s.i+2+end]
+			s.i += end + 4
+			if strings.EqualFold(strings.TrimSpace(comment), "GraphQL") {
+				s.magicComment = true
+			}
+			continue
+		case character == '\'' || character == '"':
+			s.skipString(character)
+		case character == '`':
+			s.template()
+			continue
+		case character == '/' && s.startsRegex():
+			s.skipRegex()
+		case isWordCharacter(character):
+			start := s.i
+			for s.i < len(s.content) && isWordCharacter(s.content[s.i]) {
+				s.i++
+			}
+
+			s.lastWord = s.content[start:s.i]
+			s.lastPunct = 0
+			s.magicComment = false
+			continue
+		case character == ' ' || character == '\t' || character == '\n' || character == '\r':
+			s.i++
+			continue
+		default:
+			if nested && character == '{' {
+				depth++
+			}
+			if nested && character == '}' {
+				if depth == 0 {
+					s.i++
+					return
+				}
+				depth--
+			}
+
+			s.wordBeforeCall = ""
+			if character == '(' {
+				s.wordBeforeCall = s.lastWord
+			}
+
+			s.lastWord = ""
+			s.lastPunct = character
+			s.magicComment = false
+		}
+
+		s.i++
+	}
+}
+
+/*
+template reads a template literal starting at its backtick, and keeps it if it holds GraphQL
+*/
+func (s *templateScanner) template() {
+	isGraphQL := s.magicComment ||
+		slices.Contains(gqlIdentifiers, s.lastWord) ||
+		(s.lastPunct == '(' && slices.Contains(gqlIdentifiers, s.wordBeforeCall))
+
+	s.i++
+	start := s.i
+	query := strings.Builder{}
+
+	for s.i < len(s.content) {
+		character := s.content[s.i]
+
+		if character == '\\' {
+			query.WriteString(s.content[s.i:min(s.i+2, len(s.content))])
+			s.i += 2
+			continue
+		}
+
+		if character == '`' {
+			break
+		}
+
+		if character == '$' && s.peek(1) == '{' {
+			interpolationStart := s.i
+			s.i += 2
+			s.lastWord, s.lastPunct, s.wordBeforeCall, s.magicComment = "", '{', "", false
+			s.scan(true)
+
+			// interpolations are blanked out, keeping line breaks so positions still match the file
+			for _, interpolated := range []byte(s.content[interpolationStart:min(s.i, len(s.content))]) {
+				if interpolated == '\n' {
+					query.WriteByte('\n')
+				} else {
+					query.WriteByte(' ')
+				}
+			}
+			continue
+		}
+
+		query.WriteByte(character)
+		s.i++
+	}
+
+	end := min(s.i, len(s.content))
+	if isGraphQL {
+		s.documents = append(s.documents, ExtractedDocument{
+			Raw:    s.content[start:end],
+			Query:  query.String(),
+			Offset: start,
+		})
+	}
+
+	s.i = end + 1
+	s.lastWord, s.lastPunct, s.wordBeforeCall, s.magicComment = "", '`', "", false
+}
+
+/*
+skipString skips a quoted string, a string ending at a line break is not a string but JSX text such as don't
+*/
+func (s *templateScanner) skipString(quote byte) {
+	for j := s.i + 1; j < len(s.content); j++ {
+		switch s.content[j] {
+		case '\\':
+			j++
+		case '\n':
+			return
+		case quote:
+			s.i = j
+			s.lastWord, s.lastPunct = "", quote
+			return
+		}
+	}
+}
+
+func (s *templateScanner) skipRegex() {
+	inClass := false
+	for j := s.i + 1; j < len(s.content); j++ {
+		switch s.content[j] {
+		case '\\':
+			j++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '\n':
+			return
+		case '/':
+			if !inClass {
+				s.i = j
+				s.lastWord, s.lastPunct = "", '/'
+				return
+			}
+		}
+	}
+}
+
+/*
+startsRegex checks if a slash starts a regular expression literal rather than dividing
+*/
+func (s *templateScanner) startsRegex() bool {
+	if s.lastWord != "" {
+		return slices.Contains(regexKeywords, s.lastWord)
+	}
+
+	return s.lastPunct != ')' && s.lastPunct != ']' && s.lastPunct != '}' && s.lastPunct != '`' &&
+		s.lastPunct != '\'' && s.lastPunct != '"'
+}
+
+func (s *templateScanner) skipUntil(end string) {
+	index := strings.Index(s.content[s.i:], end)
+	if index == -1 {
+		s.i = len(s.content)
+		return
+	}
+
+	s.i += index
+}
+
+func (s *templateScanner) peek(offset int) byte {
+	if s.i+offset >= len(s.content) {
+		return 0
+	}
+
+	return s.content[s.i+offset]
+}
+
+func isWordCharacter(character byte) bool {
+	return character == '_' || character == '$' || character >= 0x80 ||
+		(character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z') || (character >= '0' && character <= '9')
+}
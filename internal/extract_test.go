@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestExtractDocuments tests which template literals are taken as GraphQL
+func TestExtractDocuments(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "TaggedTemplates",
+			input:    "const a = gql`query A { a }`;\nconst b = graphql`query B { b }`;\nconst c = css`color: red;`;",
+			expected: []string{"query A { a }", "query B { b }"},
+		},
+		{
+			name:     "FunctionCall",
+			input:    "const a = graphql(`query A { a }`);\nconst b = format(`query B { b }`);",
+			expected: []string{"query A { a }"},
+		},
+		{
+			name:     "MagicComment",
+			input:    "const a = /* GraphQL */ `query A { a }`;\nconst b = /* CSS */ `query B { b }`;",
+			expected: []string{"query A { a }"},
+		},
+		{
+			name:     "Interpolations",
+			input:    "const a = gql`\n  query A { ...F }\n  ${fragment({ nested: `query B { b }` })}\n`;",
+			expected: []string{"\n  query A { ...F }\n" + strings.Repeat(" ", len("  ${fragment({ nested: `query B { b }` })}")) + "\n"},
+		},
+		{
+			name: "StringsCommentsAndRegexes",
+			input: "// gql`query A { a }`\nconst s = 'gql`query B { b }`';\nconst r = /`/g;\n" +
+				"const jsx = <p>Don't {gql`query C { c }`}</p>;\nconst d = 4 / 2 / gql`query D { d }`;",
+			expected: []string{"query C { c }", "query D { d }"},
+		},
+		{
+			name: "RegexesWithBackticks",
+			input: "const a = s.replace(/`/g, '');\nconst b = [/[`/]+/, /\\`/];\nconst c = ok && /`(a)`/.test(s) ? 1 : 2;\n" +
+				"function d() { return /`/u; }\nconst e = gql`query E { e }`;",
+			expected: []string{"query E { e }"},
+		},
+		{
+			name: "NestedTemplates",
+			input: "const a = gql`query A { a ${`b ${`c ${d}`} }`} }`;\n" +
+				"const e = `${cond ? { f: `g` }.f : `}`}`;\n" +
+				"const h = html`<p>${gql`query H { h }`}</p>`;",
+			expected: []string{"query A { a " + strings.Repeat(" ", len("${`b ${`c ${d}`} }`}")) + " }", "query H { h }"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []string
+			for _, document := range ExtractDocuments(tt.input) {
+				queries = append(queries, document.Query)
+
+				if tt.input[document.Offset:document.Offset+len(document.Raw)] != document.Raw {
+					t.Errorf("ExtractDocuments() offset %d does not point at %q", document.Offset, document.Raw)
+				}
+			}
+
+			if !slices.Equal(queries, tt.expected) {
+				t.Errorf("ExtractDocuments() = %q, expected %q", queries, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"errors"
+	"github.com/bmatcuk/doublestar/v4"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+/*
+ExpandGlobs resolves paths and glob patterns relative to rootDir into a list of absolute file paths, in the order of
+the patterns with the matches of a glob sorted. A file matched by several patterns is listed where it is first matched.
+Patterns prefixed with ! exclude files matched by any other pattern, URLs are passed through unchanged.
+*/
+func ExpandGlobs(rootDir string, patterns []string) ([]string, error) {
+	var includes []string
+	var excludes []string
+
+	for _, pattern := range patterns {
+		if negatedPattern, ok := strings.CutPrefix(pattern, "!"); ok {
+			excludes = append(excludes, resolvePath(rootDir, negatedPattern))
+		} else {
+			includes = append(includes, pattern)
+		}
+	}
+
+	var files []string
+	for _, pattern := range includes {
+		if IsURL(pattern) {
+			files = append(files, pattern)
+			continue
+		}
+
+		// plain paths are kept even if they do not exist, so the error surfaces when they are read
+		if !isGlob(pattern) {
+			files = append(files, resolvePath(rootDir, pattern))
+			continue
+		}
+
+		matches, err := doublestar.FilepathGlob(resolvePath(rootDir, pattern), doublestar.WithFilesOnly())
+		if err != nil {
+			return nil, errors.New("invalid glob pattern " + pattern + ": " + err.Error())
+		}
+
+		slices.Sort(matches)
+		files = append(files, matches...)
+	}
+
+	files = slices.DeleteFunc(files, func(file string) bool {
+		for _, exclude := range excludes {
+			if matched, _ := doublestar.PathMatch(exclude, file); matched {
+				return true
+			}
+		}
+
+		return false
+	})
+
+	var expanded []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if !seen[file] {
+			seen[file] = true
+			expanded = append(expanded, file)
+		}
+	}
+
+	return expanded, nil
+}
+
+/*
+MatchGlobs checks if a file would be among the files ExpandGlobs resolves the patterns to, without reading the disk.
+This picks up files that are created after the patterns were expanded.
+*/
+func MatchGlobs(rootDir string, patterns []string, file string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negatedPattern, negated := strings.CutPrefix(pattern, "!")
+		if IsURL(negatedPattern) {
+			continue
+		}
+
+		resolvedPattern := resolvePath(rootDir, negatedPattern)
+		matches := resolvedPattern == file
+		if isGlob(negatedPattern) {
+			matches, _ = doublestar.PathMatch(resolvedPattern, file)
+		}
+
+		if matches && negated {
+			return false
+		}
+		matched = matched || matches
+	}
+
+	return matched
+}
+
+/*
+GlobDirs returns the directories that files matched by the glob patterns are searched from, the part of each pattern
+before its first wildcard
+*/
+func GlobDirs(rootDir string, patterns []string) []string {
+	var dirs []string
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") || IsURL(pattern) || !isGlob(pattern) {
+			continue
+		}
+
+		base, _ := doublestar.SplitPattern(filepath.ToSlash(resolvePath(rootDir, pattern)))
+		dirs = append(dirs, filepath.FromSlash(base))
+	}
+
+	slices.Sort(dirs)
+
+	return slices.Compact(dirs)
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[{")
+}
+
+func resolvePath(rootDir string, filePath string) string {
+	if filepath.IsAbs(filePath) {
+		return filepath.Clean(filePath)
+	}
+
+	return filepath.Join(rootDir, filePath)
+}
@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestExpandGlobs tests glob expansion and negation relative to a root dir
+func TestExpandGlobs(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"schemas/base.graphql":          "",
+		"schemas/search/search.graphql": "",
+		"schemas/legacy/old.graphql":    "",
+		"schemas/readme.md":             "",
+	})
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "PlainPath",
+			patterns: []string{"schemas/base.graphql"},
+			expected: []string{"schemas/base.graphql"},
+		},
+		{
+			name:     "MissingPlainPathIsKept",
+			patterns: []string{"missing.graphql"},
+			expected: []string{"missing.graphql"},
+		},
+		{
+			name:     "DoubleStar",
+			patterns: []string{"schemas/**/*.graphql"},
+			expected: []string{"schemas/base.graphql", "schemas/legacy/old.graphql", "schemas/search/search.graphql"},
+		},
+		{
+			name:     "Negation",
+			patterns: []string{"schemas/**/*.graphql", "!schemas/legacy/**"},
+			expected: []string{"schemas/base.graphql", "schemas/search/search.graphql"},
+		},
+		{
+			name:     "Duplicates",
+			patterns: []string{"schemas/*.graphql", "schemas/base.graphql"},
+			expected: []string{"schemas/base.graphql"},
+		},
+		{
+			name:     "PatternOrder",
+			patterns: []string{"schemas/search/*.graphql", "schemas/**/*.graphql"},
+			expected: []string{"schemas/search/search.graphql", "schemas/base.graphql", "schemas/legacy/old.graphql"},
+		},
+		{
+			name:     "InvalidPattern",
+			patterns: []string{"schemas/[.graphql"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ExpandGlobs(dir, tt.patterns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandGlobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var expected []string
+			for _, file := range tt.expected {
+				expected = append(expected, filepath.Join(dir, file))
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(result, expected) {
+				t.Errorf("ExpandGlobs() = %v, expected %v", result, expected)
+			}
+		})
+	}
+}
+
+// TestMatchGlobs tests that files are matched like ExpandGlobs would match them, whether they exist or not
+func TestMatchGlobs(t *testing.T) {
+	dir := t.TempDir()
+	patterns := []string{"schemas/**/*.graphql", "!schemas/legacy/**", "extra.graphql", "https://example.com/graphql"}
+
+	tests := []struct {
+		name     string
+		file     string
+		expected bool
+	}{
+		{name: "Glob", file: "schemas/new.graphql", expected: true},
+		{name: "NestedGlob", file: "schemas/search/new.graphql", expected: true},
+		{name: "Negation", file: "schemas/legacy/new.graphql", expected: false},
+		{name: "PlainPath", file: "extra.graphql", expected: true},
+		{name: "OtherExtension", file: "schemas/readme.md", expected: false},
+		{name: "OtherDir", file: "other/new.graphql", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if matched := MatchGlobs(dir, patterns, filepath.Join(dir, tt.file)); matched != tt.expected {
+				t.Errorf("MatchGlobs() = %v, expected %v", matched, tt.expected)
+			}
+		})
+	}
+
+	if dirs := GlobDirs(dir, patterns); !reflect.DeepEqual(dirs, []string{filepath.Join(dir, "schemas")}) {
+		t.Errorf("GlobDirs() = %v, expected the schemas dir", dirs)
+	}
+}
@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/simse/faster-graphql-codegen/internal/plugins"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/*
+DefaultHeader is written at the top of generated files unless a header is configured
+*/
+const DefaultHeader = "Generated by faster-graphql-codegen"
+
+/*
+Header is the comment written at the top of generated files. It is configured as false to disable it, or as text
+that may contain the placeholders {hash}, a hash of the inputs, and {timestamp}, the time of generation.
+*/
+type Header struct {
+	Text     string
+	Disabled bool
+}
+
+func (h *Header) UnmarshalYAML(value *yaml.Node) error {
+	var headerValue interface{}
+	if err := value.Decode(&headerValue); err != nil {
+		return err
+	}
+
+	header, err := getHeader(headerValue)
+	if err != nil {
+		return err
+	}
+
+	*h = header
+
+	return nil
+}
+
+// Helper function to get a header from a boolean or a string
+func getHeader(value interface{}) (Header, error) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return Header{Text: DefaultHeader}, nil
+		}
+		return Header{Disabled: true}, nil
+	case string:
+		return Header{Text: v}, nil
+	default:
+		return Header{}, fmt.Errorf("value is not a boolean or a string")
+	}
+}
+
+/*
+HeaderFor returns the header of a generates entry, the entry overrides the root config
+*/
+func (c Config) HeaderFor(generates Generates) Header {
+	if generates.Header != nil {
+		return *generates.Header
+	}
+
+	if c.Header != nil {
+		return *c.Header
+	}
+
+	return Header{Text: DefaultHeader}
+}
+
+/*
+Render replaces the placeholders of the header, inputHash identifies the inputs the output was generated from
+*/
+func (h Header) Render(inputHash string) string {
+	if h.Disabled {
+		return ""
+	}
+
+	// a shortened hash is plenty to tell inputs apart
+	if len(inputHash) > 16 {
+		inputHash = inputHash[:16]
+	}
+
+	return strings.NewReplacer(
+		"{hash}", inputHash,
+		"{timestamp}", time.Now().Format(time.DateTime),
+	).Replace(h.Text)
+}
+
+/*
+InputHash hashes everything the output of a generates entry is generated from: the schema, given by the hash of its
+sources, the documents as written, and the plugins of the entry with the config they run with. Documents are named
+relative to rootDir, so a project hashes the same wherever it is checked out.
+*/
+func (c Config) InputHash(rootDir string, schemaHash string, documents []*plugins.Document, generates Generates) string {
+	hash := sha256.New()
+
+	hash.Write([]byte(schemaHash))
+	hash.Write([]byte{0})
+
+	for _, document := range documents {
+		file, err := filepath.Rel(rootDir, document.File)
+		if err != nil {
+			file = document.File
+		}
+
+		hash.Write([]byte(filepath.ToSlash(file)))
+		hash.Write([]byte{0})
+		hash.Write([]byte(document.Raw))
+		hash.Write([]byte{0})
+	}
+
+	for _, plugin := range generates.Plugins {
+		// maps are encoded with sorted keys, so the same config always hashes the same
+		pluginConfig, _ := json.Marshal(c.PluginConfig(generates, plugin))
+
+		hash.Write([]byte(plugin))
+		hash.Write([]byte{0})
+		hash.Write(pluginConfig)
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+/*
+HeaderComment formats header text as a comment in the language of destination, it is empty for languages without
+comments, such as JSON
+*/
+func HeaderComment(destination string, text string) string {
+	if text == "" {
+		return ""
+	}
+
+	switch strings.ToLower(filepath.Ext(destination)) {
+	case ".ts", ".tsx", ".mts", ".cts", ".js", ".jsx", ".mjs", ".cjs":
+		return "/* " + strings.ReplaceAll(text, "*/", "* /") + " */\n"
+	case ".graphql", ".gql", ".graphqls":
+		return "# " + strings.ReplaceAll(text, "\n", "\n# ") + "\n"
+	default:
+		return ""
+	}
+}
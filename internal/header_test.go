@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"github.com/simse/faster-graphql-codegen/internal/plugins"
+	"testing"
+)
+
+// TestHeader tests parsing and rendering of configured headers
+func TestHeader(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		destination string
+		expected    string
+	}{
+		{
+			name:        "Default",
+			input:       "generates:\n  out.ts:\n    plugins: [typescript]\n",
+			destination: "out.ts",
+			expected:    "/* Generated by faster-graphql-codegen */\n",
+		},
+		{
+			name:        "Disabled",
+			input:       "header: false\ngenerates:\n  out.ts:\n    plugins: [typescript]\n",
+			destination: "out.ts",
+			expected:    "",
+		},
+		{
+			name:        "StaticText",
+			input:       "generates:\n  out.ts:\n    header: Do not edit\n    plugins: [typescript]\n",
+			destination: "out.ts",
+			expected:    "/* Do not edit */\n",
+		},
+		{
+			name:        "Hash",
+			input:       "header: 'Generated from {hash}'\ngenerates:\n  out.graphql:\n    plugins: [schema-ast]\n",
+			destination: "out.graphql",
+			expected:    "# Generated from 0123456789abcdef\n",
+		},
+		{
+			name:        "GraphQLSchemaExtension",
+			input:       "generates:\n  schema.graphqls:\n    plugins: [schema-ast]\n",
+			destination: "schema.graphqls",
+			expected:    "# Generated by faster-graphql-codegen\n",
+		},
+		{
+			name:        "OutputOverridesRoot",
+			input:       "header: false\ngenerates:\n  out.ts:\n    header: true\n    plugins: [typescript]\n",
+			destination: "out.ts",
+			expected:    "/* Generated by faster-graphql-codegen */\n",
+		},
+		{
+			name:        "NoCommentsInJSON",
+			input:       "generates:\n  introspection.json:\n    plugins: [introspection]\n",
+			destination: "introspection.json",
+			expected:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseYAMLConfig([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("ParseYAMLConfig() error = %v", err)
+			}
+
+			header := config.HeaderFor(config.Generates[tt.destination]).Render("0123456789abcdef0123456789abcdef")
+			if result := HeaderComment(tt.destination, header); result != tt.expected {
+				t.Errorf("HeaderComment() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHeaderInvalidType(t *testing.T) {
+	if _, err := ParseYAMLConfig([]byte("header: [a, b]\n")); err == nil {
+		t.Errorf("ParseYAMLConfig() expected an error for a list header")
+	}
+}
+
+// TestInputHash tests that the input hash changes with the schema, the documents and the plugin config
+func TestInputHash(t *testing.T) {
+	documents := []*plugins.Document{{File: "hello.graphql", Raw: "query Hello { hello }"}}
+	generates := Generates{Plugins: []string{"typescript"}, Config: map[string]interface{}{"skipTypename": true}}
+	base := Config{}.InputHash("/project", "schema", documents, generates)
+
+	tests := []struct {
+		name      string
+		config    Config
+		schema    string
+		documents []*plugins.Document
+		generates Generates
+	}{
+		{"Schema", Config{}, "changed", documents, generates},
+		{"Documents", Config{}, "schema", []*plugins.Document{{File: "hello.graphql", Raw: "query Hello { goodbye }"}}, generates},
+		{"Plugins", Config{}, "schema", documents, Generates{Plugins: []string{"typescript-operations"}, Config: generates.Config}},
+		{"RootConfig", Config{Config: map[string]interface{}{"scalars": "any"}}, "schema", documents, generates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if hash := tt.config.InputHash("/project", tt.schema, tt.documents, tt.generates); hash == base {
+				t.Errorf("InputHash() = %s, expected it to differ from %s", hash, base)
+			}
+		})
+	}
+
+	if hash := (Config{}).InputHash("/project", "schema", documents, generates); hash != base {
+		t.Errorf("InputHash() = %s, expected the same inputs to hash to %s", hash, base)
+	}
+
+	// the same project checked out elsewhere
+	movedDocuments := []*plugins.Document{{File: "/elsewhere/project/hello.graphql", Raw: "query Hello { hello }"}}
+	moved := Config{}.InputHash("/elsewhere/project", "schema", movedDocuments, generates)
+	if original := (Config{}).InputHash("/project", "schema", []*plugins.Document{{File: "/project/hello.graphql", Raw: "query Hello { hello }"}}, generates); moved != original {
+		t.Errorf("InputHash() = %s in another root, expected %s", moved, original)
+	}
+}
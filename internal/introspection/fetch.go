@@ -0,0 +1,165 @@
+package introspection
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+/*
+Query is the standard introspection query, as produced by getIntrospectionQuery() in graphql-js
+*/
+const Query = `
+    query IntrospectionQuery {
+      __schema {
+        queryType { name }
+        mutationType { name }
+        subscriptionType { name }
+        types {
+          ...FullType
+        }
+        directives {
+          name
+          description
+          locations
+          args {
+            ...InputValue
+          }
+        }
+      }
+    }
+
+    fragment FullType on __Type {
+      kind
+      name
+      description
+      fields(includeDeprecated: true) {
+        name
+        description
+        args {
+          ...InputValue
+        }
+        type {
+          ...TypeRef
+        }
+        isDeprecated
+        deprecationReason
+      }
+      inputFields {
+        ...InputValue
+      }
+      interfaces {
+        ...TypeRef
+      }
+      enumValues(includeDeprecated: true) {
+        name
+        description
+        isDeprecated
+        deprecationReason
+      }
+      possibleTypes {
+        ...TypeRef
+      }
+    }
+
+    fragment InputValue on __InputValue {
+      name
+      description
+      type { ...TypeRef }
+      defaultValue
+    }
+
+    fragment TypeRef on __Type {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType {
+                kind
+                name
+                ofType {
+                  kind
+                  name
+                  ofType {
+                    kind
+                    name
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  `
+
+type queryRequest struct {
+	OperationName string `json:"operationName"`
+	Query         string `json:"query"`
+}
+
+type queryErrors struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+/*
+Fetch runs the introspection query against a GraphQL endpoint and returns the raw response body
+*/
+func Fetch(client *http.Client, url string, headers map[string]string) ([]byte, error) {
+	requestBody, err := json.Marshal(queryRequest{
+		OperationName: "IntrospectionQuery",
+		Query:         Query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection query to %s failed with status %s", url, response.Status)
+	}
+
+	// a server may answer with errors instead of data, e.g. when introspection is disabled
+	parsedErrors := queryErrors{}
+	if err := json.Unmarshal(responseBody, &parsedErrors); err == nil && len(parsedErrors.Errors) > 0 {
+		return nil, errors.New("introspection query to " + url + " returned an error: " + parsedErrors.Errors[0].Message)
+	}
+
+	return responseBody, nil
+}
@@ -0,0 +1,83 @@
+package introspection
+
+/*
+Result is the response body of an introspection query, either wrapped in data or bare
+*/
+type Result struct {
+	Data   *Data   `json:"data,omitempty"`
+	Schema *Schema `json:"__schema,omitempty"`
+}
+
+type Data struct {
+	Schema *Schema `json:"__schema"`
+}
+
+type Schema struct {
+	QueryType        *TypeName   `json:"queryType"`
+	MutationType     *TypeName   `json:"mutationType"`
+	SubscriptionType *TypeName   `json:"subscriptionType"`
+	Types            []FullType  `json:"types"`
+	Directives       []Directive `json:"directives"`
+}
+
+type TypeName struct {
+	Name string `json:"name"`
+}
+
+type FullType struct {
+	Kind          string       `json:"kind"`
+	Name          string       `json:"name"`
+	Description   *string      `json:"description"`
+	Fields        []Field      `json:"fields"`
+	InputFields   []InputValue `json:"inputFields"`
+	Interfaces    []TypeRef    `json:"interfaces"`
+	EnumValues    []EnumValue  `json:"enumValues"`
+	PossibleTypes []TypeRef    `json:"possibleTypes"`
+}
+
+type Field struct {
+	Name              string       `json:"name"`
+	Description       *string      `json:"description"`
+	Args              []InputValue `json:"args"`
+	Type              TypeRef      `json:"type"`
+	IsDeprecated      bool         `json:"isDeprecated"`
+	DeprecationReason *string      `json:"deprecationReason"`
+}
+
+type InputValue struct {
+	Name         string  `json:"name"`
+	Description  *string `json:"description"`
+	Type         TypeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+type EnumValue struct {
+	Name              string  `json:"name"`
+	Description       *string `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   *string  `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+type Directive struct {
+	Name        string       `json:"name"`
+	Description *string      `json:"description"`
+	Locations   []string     `json:"locations"`
+	Args        []InputValue `json:"args"`
+}
+
+/*
+GetSchema returns the __schema object of the result, regardless of whether it was wrapped in data
+*/
+func (r *Result) GetSchema() *Schema {
+	if r.Data != nil && r.Data.Schema != nil {
+		return r.Data.Schema
+	}
+
+	return r.Schema
+}
@@ -0,0 +1,291 @@
+package introspection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"slices"
+	"strings"
+)
+
+var builtInScalars = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+var builtInDirectives = []string{"include", "skip", "deprecated", "specifiedBy", "defer", "oneOf"}
+
+/*
+ParseSchemaDocument reads an introspection result from source and converts it to a schema document
+*/
+func ParseSchemaDocument(source *ast.Source) (*ast.SchemaDocument, error) {
+	result := Result{}
+	if err := json.Unmarshal([]byte(source.Input), &result); err != nil {
+		return nil, fmt.Errorf("%s: could not parse introspection json: %w", source.Name, err)
+	}
+
+	schema := result.GetSchema()
+	if schema == nil {
+		return nil, errors.New(source.Name + ": introspection json does not contain a __schema object")
+	}
+
+	return ToSchemaDocument(schema, source)
+}
+
+/*
+ToSchemaDocument converts an introspection schema to a schema document, every node is positioned in source
+*/
+func ToSchemaDocument(schema *Schema, source *ast.Source) (*ast.SchemaDocument, error) {
+	position := &ast.Position{Src: source}
+	document := &ast.SchemaDocument{}
+
+	for i, fullType := range schema.Types {
+		if strings.HasPrefix(fullType.Name, "__") || slices.Contains(builtInScalars, fullType.Name) {
+			continue
+		}
+
+		// the index stands in for the offset, so types keep the order of the introspection result
+		definition, err := convertType(fullType, &ast.Position{Src: source, Start: i})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source.Name, err)
+		}
+
+		document.Definitions = append(document.Definitions, definition)
+	}
+
+	for _, directive := range schema.Directives {
+		if slices.Contains(builtInDirectives, directive.Name) {
+			continue
+		}
+
+		arguments, err := convertInputValues(directive.Args, position)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source.Name, err)
+		}
+
+		directiveDefinition := &ast.DirectiveDefinition{
+			Description: stringValue(directive.Description),
+			Name:        directive.Name,
+			Arguments:   arguments,
+			Position:    position,
+		}
+		for _, location := range directive.Locations {
+			directiveDefinition.Locations = append(directiveDefinition.Locations, ast.DirectiveLocation(location))
+		}
+
+		document.Directives = append(document.Directives, directiveDefinition)
+	}
+
+	// a schema definition is only needed when root types are not named by convention
+	rootTypes := []struct {
+		operation        ast.Operation
+		typeName         *TypeName
+		conventionalName string
+	}{
+		{ast.Query, schema.QueryType, "Query"},
+		{ast.Mutation, schema.MutationType, "Mutation"},
+		{ast.Subscription, schema.SubscriptionType, "Subscription"},
+	}
+
+	schemaDefinition := &ast.SchemaDefinition{Position: position}
+	hasCustomRootType := false
+	for _, rootType := range rootTypes {
+		if rootType.typeName == nil {
+			continue
+		}
+
+		hasCustomRootType = hasCustomRootType || rootType.typeName.Name != rootType.conventionalName
+		schemaDefinition.OperationTypes = append(schemaDefinition.OperationTypes, &ast.OperationTypeDefinition{
+			Operation: rootType.operation,
+			Type:      rootType.typeName.Name,
+			Position:  position,
+		})
+	}
+
+	if hasCustomRootType {
+		document.Schema = append(document.Schema, schemaDefinition)
+	}
+
+	return document, nil
+}
+
+func convertType(fullType FullType, position *ast.Position) (*ast.Definition, error) {
+	definition := &ast.Definition{
+		Kind:        ast.DefinitionKind(fullType.Kind),
+		Description: stringValue(fullType.Description),
+		Name:        fullType.Name,
+		Position:    position,
+	}
+
+	switch definition.Kind {
+	case ast.Scalar, ast.Object, ast.Interface, ast.Union, ast.Enum, ast.InputObject:
+	default:
+		return nil, fmt.Errorf("type %s has unknown kind %s", fullType.Name, fullType.Kind)
+	}
+
+	for _, field := range fullType.Fields {
+		fieldType, err := convertTypeRef(field.Type, position)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", fullType.Name, field.Name, err)
+		}
+
+		arguments, err := convertInputValues(field.Args, position)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", fullType.Name, field.Name, err)
+		}
+
+		definition.Fields = append(definition.Fields, &ast.FieldDefinition{
+			Description: stringValue(field.Description),
+			Name:        field.Name,
+			Arguments:   arguments,
+			Type:        fieldType,
+			Directives:  deprecatedDirective(field.IsDeprecated, field.DeprecationReason, position),
+			Position:    position,
+		})
+	}
+
+	inputFields, err := convertInputValues(fullType.InputFields, position)
+	if err != nil {
+		return nil, fmt.Errorf("type %s: %w", fullType.Name, err)
+	}
+	for _, inputField := range inputFields {
+		definition.Fields = append(definition.Fields, &ast.FieldDefinition{
+			Description:  inputField.Description,
+			Name:         inputField.Name,
+			DefaultValue: inputField.DefaultValue,
+			Type:         inputField.Type,
+			Position:     position,
+		})
+	}
+
+	for _, typeRef := range fullType.Interfaces {
+		definition.Interfaces = append(definition.Interfaces, stringValue(typeRef.Name))
+	}
+
+	if definition.Kind == ast.Union {
+		for _, typeRef := range fullType.PossibleTypes {
+			definition.Types = append(definition.Types, stringValue(typeRef.Name))
+		}
+	}
+
+	for _, enumValue := range fullType.EnumValues {
+		definition.EnumValues = append(definition.EnumValues, &ast.EnumValueDefinition{
+			Description: stringValue(enumValue.Description),
+			Name:        enumValue.Name,
+			Directives:  deprecatedDirective(enumValue.IsDeprecated, enumValue.DeprecationReason, position),
+			Position:    position,
+		})
+	}
+
+	return definition, nil
+}
+
+func convertInputValues(inputValues []InputValue, position *ast.Position) (ast.ArgumentDefinitionList, error) {
+	var arguments ast.ArgumentDefinitionList
+
+	for _, inputValue := range inputValues {
+		argumentType, err := convertTypeRef(inputValue.Type, position)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %w", inputValue.Name, err)
+		}
+
+		argument := &ast.ArgumentDefinition{
+			Description: stringValue(inputValue.Description),
+			Name:        inputValue.Name,
+			Type:        argumentType,
+			Position:    position,
+		}
+
+		if inputValue.DefaultValue != nil {
+			argument.DefaultValue, err = parseValue(*inputValue.DefaultValue)
+			if err != nil {
+				return nil, fmt.Errorf("default value of argument %s: %w", inputValue.Name, err)
+			}
+		}
+
+		arguments = append(arguments, argument)
+	}
+
+	return arguments, nil
+}
+
+func convertTypeRef(typeRef TypeRef, position *ast.Position) (*ast.Type, error) {
+	switch typeRef.Kind {
+	case "NON_NULL":
+		if typeRef.OfType == nil {
+			return nil, errors.New("NON_NULL type is missing ofType")
+		}
+
+		innerType, err := convertTypeRef(*typeRef.OfType, position)
+		if err != nil {
+			return nil, err
+		}
+
+		innerType.NonNull = true
+		return innerType, nil
+	case "LIST":
+		if typeRef.OfType == nil {
+			return nil, errors.New("LIST type is missing ofType")
+		}
+
+		elemType, err := convertTypeRef(*typeRef.OfType, position)
+		if err != nil {
+			return nil, err
+		}
+
+		return ast.ListType(elemType, position), nil
+	default:
+		if typeRef.Name == nil {
+			return nil, errors.New(typeRef.Kind + " type is missing a name")
+		}
+
+		return ast.NamedType(*typeRef.Name, position), nil
+	}
+}
+
+/*
+parseValue parses a GraphQL value literal, as found in the defaultValue of an introspection result
+*/
+func parseValue(raw string) (*ast.Value, error) {
+	query, err := parser.ParseQuery(&ast.Source{Input: "{ f(v: " + raw + ") }"})
+	if err != nil {
+		return nil, err
+	}
+
+	field, ok := query.Operations[0].SelectionSet[0].(*ast.Field)
+	if !ok || len(field.Arguments) != 1 {
+		return nil, errors.New("invalid value " + raw)
+	}
+
+	return field.Arguments[0].Value, nil
+}
+
+func deprecatedDirective(isDeprecated bool, reason *string, position *ast.Position) ast.DirectiveList {
+	if !isDeprecated {
+		return nil
+	}
+
+	directive := &ast.Directive{
+		Name:     "deprecated",
+		Position: position,
+	}
+
+	if reason != nil {
+		directive.Arguments = ast.ArgumentList{
+			{
+				Name:     "reason",
+				Value:    &ast.Value{Kind: ast.StringValue, Raw: *reason, Position: position},
+				Position: position,
+			},
+		}
+	}
+
+	return ast.DirectiveList{directive}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
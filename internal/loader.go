@@ -1,35 +1,257 @@
 package internal
 
 import (
-	"github.com/vektah/gqlparser/v2"
+	"github.com/simse/faster-graphql-codegen/internal/introspection"
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
 )
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 )
 
+type SchemaLoadOptions struct {
+	// Headers holds the HTTP headers to send per schema URL
+	Headers map[string]map[string]string
+	Client  *http.Client
+	// Cache is used to skip parsing when the sources are unchanged, nil disables caching
+	Cache *SchemaCache
+}
+
+/*
+LoadSchema reads every input file and merges them into a single schema
+*/
 func LoadSchema(inputs ...string) (*ast.Schema, error) {
+	return LoadSchemaWithOptions(SchemaLoadOptions{}, inputs...)
+}
+
+/*
+LoadSchemaWithOptions reads every input, either a file or a URL to introspect, and merges them into a single schema
+*/
+func LoadSchemaWithOptions(options SchemaLoadOptions, inputs ...string) (*ast.Schema, error) {
+	sources, err := ReadSchemaSources(options, inputs...)
+	if err != nil {
+		return &ast.Schema{}, err
+	}
+
+	return LoadSchemaSourcesWithCache(options.Cache, sources...)
+}
+
+/*
+ReadSchemaSources reads every input, either a file or a URL to introspect, into a source named after the input
+*/
+func ReadSchemaSources(options SchemaLoadOptions, inputs ...string) ([]*ast.Source, error) {
 	if len(inputs) == 0 {
-		return &ast.Schema{}, errors.New("no inputs given to load")
+		return nil, errors.New("no inputs given to load")
 	}
 
-	inputToLoad := inputs[0]
+	sources := make([]*ast.Source, 0, len(inputs))
+	for _, input := range inputs {
+		dat, err := readSchemaInput(options, input)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, &ast.Source{
+			BuiltIn: false,
+			Input:   string(dat),
+			Name:    input,
+		})
+	}
+
+	return sources, nil
+}
+
+/*
+LoadSchemaSourcesWithCache loads sources like LoadSchemaSources, but reads the schema from cache if the sources are
+unchanged. A nil cache disables caching.
+*/
+func LoadSchemaSourcesWithCache(cache *SchemaCache, sources ...*ast.Source) (*ast.Schema, error) {
+	if cache == nil {
+		return LoadSchemaSources(sources...)
+	}
+
+	cacheKey := HashSources(sources)
+	if schema, ok := cache.Get(cacheKey); ok {
+		return schema, nil
+	}
 
-	// load file
-	dat, err := os.ReadFile(inputToLoad)
+	schema, err := LoadSchemaSources(sources...)
 	if err != nil {
-		return &ast.Schema{}, err
+		return schema, err
+	}
+
+	// a failed write only costs a parse on the next run
+	if cacheErr := cache.Put(cacheKey, schema); cacheErr != nil {
+		slog.Warn("could not write schema cache", "error", cacheErr)
+	}
+
+	return schema, nil
+}
+
+/*
+HashSources hashes the name and content of every source
+*/
+func HashSources(sources []*ast.Source) string {
+	hash := sha256.New()
+
+	for _, source := range sources {
+		hash.Write([]byte(source.Name))
+		hash.Write([]byte{0})
+		hash.Write([]byte(source.Input))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+/*
+HashSourceContents hashes the content of every source in order, leaving out their names, so the same schema hashes
+the same wherever it is checked out
+*/
+func HashSourceContents(sources []*ast.Source) string {
+	hash := sha256.New()
+
+	for _, source := range sources {
+		hash.Write([]byte(source.Input))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func readSchemaInput(options SchemaLoadOptions, input string) ([]byte, error) {
+	if !IsURL(input) {
+		// load file
+		return os.ReadFile(input)
 	}
 
-	schema, schemaParseError := gqlparser.LoadSchema(&ast.Source{
-		BuiltIn: false,
-		Input:   string(dat),
-		Name:    "test.graphql",
-	})
+	client := options.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return introspection.Fetch(client, input, options.Headers[input])
+}
+
+/*
+IsURL checks if a schema pointer is a http(s) URL rather than a file
+*/
+func IsURL(pointer string) bool {
+	return strings.HasPrefix(pointer, "http://") || strings.HasPrefix(pointer, "https://")
+}
+
+/*
+LoadSchemaSources parses each source on its own and merges the result into one schema, type extensions may span sources
+*/
+func LoadSchemaSources(sources ...*ast.Source) (*ast.Schema, error) {
+	schemaDocument := &ast.SchemaDocument{}
+	for _, source := range append([]*ast.Source{validator.Prelude}, sources...) {
+		sourceDocument, parseErr := parseSchemaSource(source)
+		if parseErr != nil {
+			return &ast.Schema{}, toDiagnostic(parseErr, "Syntax", sources)
+		}
+
+		schemaDocument.Merge(sourceDocument)
+	}
+
+	if conflictErr := findSchemaConflicts(schemaDocument); conflictErr != nil {
+		return &ast.Schema{}, toDiagnostic(conflictErr, "SchemaConflict", sources)
+	}
+
+	schema, schemaParseError := validator.ValidateSchemaDocument(schemaDocument)
 	if schemaParseError != nil {
-		return &ast.Schema{}, schemaParseError
+		return &ast.Schema{}, toDiagnostic(schemaParseError, "SchemaValidation", sources)
 	}
 
 	return schema, nil
 }
+
+/*
+parseSchemaSource parses a source as SDL, or as an introspection result if it is a .json file or URL
+*/
+func parseSchemaSource(source *ast.Source) (*ast.SchemaDocument, error) {
+	if IsURL(source.Name) || strings.HasSuffix(strings.ToLower(source.Name), ".json") {
+		return introspection.ParseSchemaDocument(source)
+	}
+
+	return parser.ParseSchema(source)
+}
+
+/*
+findSchemaConflicts checks that no type, directive or field is declared more than once across the merged sources
+*/
+func findSchemaConflicts(schemaDocument *ast.SchemaDocument) error {
+	types := make(map[string]*ast.Definition)
+	for _, definition := range schemaDocument.Definitions {
+		if existing, ok := types[definition.Name]; ok {
+			return conflictError(definition.Position, existing.Position, "type %s", definition.Name)
+		}
+
+		types[definition.Name] = definition
+	}
+
+	directives := make(map[string]*ast.DirectiveDefinition)
+	for _, directive := range schemaDocument.Directives {
+		existing, ok := directives[directive.Name]
+
+		// built-in directives may be redeclared, gqlparser keeps the first one
+		if ok && !existing.Position.Src.BuiltIn {
+			return conflictError(directive.Position, existing.Position, "directive @%s", directive.Name)
+		}
+
+		if !ok {
+			directives[directive.Name] = directive
+		}
+	}
+
+	fields := make(map[string]*ast.FieldDefinition)
+	checkFields := func(definition *ast.Definition) error {
+		for _, field := range definition.Fields {
+			fieldKey := definition.Name + "." + field.Name
+
+			if existing, ok := fields[fieldKey]; ok {
+				return conflictError(field.Position, existing.Position, "field %s", fieldKey)
+			}
+
+			fields[fieldKey] = field
+		}
+
+		return nil
+	}
+
+	for _, definition := range schemaDocument.Definitions {
+		if err := checkFields(definition); err != nil {
+			return err
+		}
+	}
+
+	for _, extension := range schemaDocument.Extensions {
+		if err := checkFields(extension); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func conflictError(position *ast.Position, existing *ast.Position, format string, args ...interface{}) *gqlerror.Error {
+	subject := fmt.Sprintf(format, args...)
+
+	return gqlerror.ErrorPosf(
+		position,
+		"%s is defined in both %s and %s",
+		subject,
+		existing.Src.Name,
+		position.Src.Name,
+	)
+}
@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		filePath := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// TestLoadSchema tests merging of multiple schema files
+func TestLoadSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantTypes  []string
+		wantFields map[string][]string
+		wantErr    []string
+	}{
+		{
+			name: "SingleFile",
+			files: map[string]string{
+				"base.graphql": "type Query { hello: String }",
+			},
+			wantTypes: []string{"Query"},
+		},
+		{
+			name: "MultipleFiles",
+			files: map[string]string{
+				"base.graphql":   "type Query { hello: String }\ntype User { id: ID! }",
+				"search.graphql": "type SearchResult { user: User }",
+			},
+			wantTypes: []string{"Query", "User", "SearchResult"},
+		},
+		{
+			name: "ExtendAcrossFiles",
+			files: map[string]string{
+				"base.graphql":   "type Query { hello: String }",
+				"search.graphql": "extend type Query { search(term: String!): [String!]! }",
+			},
+			wantFields: map[string][]string{
+				"Query": {"hello", "search"},
+			},
+		},
+		{
+			name: "ConflictingTypes",
+			files: map[string]string{
+				"base.graphql":   "type Query { hello: String }\ntype User { id: ID! }",
+				"search.graphql": "type User { name: String }",
+			},
+			wantErr: []string{"type User", "base.graphql", "search.graphql"},
+		},
+		{
+			name: "ConflictingExtensionField",
+			files: map[string]string{
+				"base.graphql":   "type Query { hello: String }",
+				"search.graphql": "extend type Query { hello: String }",
+			},
+			wantErr: []string{"field Query.hello", "base.graphql", "search.graphql"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeTestFiles(t, tt.files)
+
+			var inputs []string
+			for name := range tt.files {
+				inputs = append(inputs, filepath.Join(dir, name))
+			}
+
+			schema, err := LoadSchema(inputs...)
+			if (err != nil) != (len(tt.wantErr) > 0) {
+				t.Fatalf("LoadSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("LoadSchema() error = %q, expected it to contain %q", err.Error(), want)
+				}
+			}
+
+			for _, typeName := range tt.wantTypes {
+				if schema.Types[typeName] == nil {
+					t.Errorf("LoadSchema() is missing type %s", typeName)
+				}
+			}
+
+			for typeName, fieldNames := range tt.wantFields {
+				for _, fieldName := range fieldNames {
+					if schema.Types[typeName].Fields.ForName(fieldName) == nil {
+						t.Errorf("LoadSchema() is missing field %s.%s", typeName, fieldName)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLoadSchemaUsesFileName(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"broken.graphql": "type Query { hello: Unknown }",
+	})
+
+	_, err := LoadSchema(filepath.Join(dir, "broken.graphql"))
+	if err == nil || !strings.Contains(err.Error(), "broken.graphql") {
+		t.Errorf("LoadSchema() error = %v, expected it to name broken.graphql", err)
+	}
+}
+
+const testIntrospectionSchema = `{
+	"queryType": { "name": "Query" },
+	"mutationType": null,
+	"subscriptionType": null,
+	"types": [
+		{
+			"kind": "OBJECT", "name": "Query", "description": "The root query",
+			"fields": [
+				{
+					"name": "users", "description": null,
+					"args": [
+						{ "name": "role", "description": null, "type": { "kind": "ENUM", "name": "Role", "ofType": null }, "defaultValue": "ADMIN" }
+					],
+					"type": { "kind": "NON_NULL", "name": null, "ofType": { "kind": "LIST", "name": null, "ofType": { "kind": "OBJECT", "name": "User", "ofType": null } } },
+					"isDeprecated": false, "deprecationReason": null
+				}
+			],
+			"inputFields": null, "interfaces": [], "enumValues": null, "possibleTypes": null
+		},
+		{
+			"kind": "OBJECT", "name": "User", "description": null,
+			"fields": [
+				{ "name": "id", "description": null, "args": [], "type": { "kind": "NON_NULL", "name": null, "ofType": { "kind": "SCALAR", "name": "ID", "ofType": null } }, "isDeprecated": false, "deprecationReason": null },
+				{ "name": "name", "description": null, "args": [], "type": { "kind": "SCALAR", "name": "String", "ofType": null }, "isDeprecated": true, "deprecationReason": "Use id" }
+			],
+			"inputFields": null, "interfaces": [], "enumValues": null, "possibleTypes": null
+		},
+		{
+			"kind": "ENUM", "name": "Role", "description": null, "fields": null, "inputFields": null, "interfaces": null,
+			"enumValues": [
+				{ "name": "ADMIN", "description": null, "isDeprecated": false, "deprecationReason": null },
+				{ "name": "GUEST", "description": null, "isDeprecated": false, "deprecationReason": null }
+			],
+			"possibleTypes": null
+		},
+		{ "kind": "SCALAR", "name": "String", "description": null, "fields": null, "inputFields": null, "interfaces": null, "enumValues": null, "possibleTypes": null },
+		{ "kind": "SCALAR", "name": "ID", "description": null, "fields": null, "inputFields": null, "interfaces": null, "enumValues": null, "possibleTypes": null }
+	],
+	"directives": []
+}`
+
+// TestLoadSchemaFromIntrospection tests loading introspection results alongside SDL files
+func TestLoadSchemaFromIntrospection(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"wrapped.json":   `{"data":{"__schema":` + testIntrospectionSchema + `}}`,
+		"bare.json":      `{"__schema":` + testIntrospectionSchema + `}`,
+		"invalid.json":   `{"message":"not a schema"}`,
+		"extend.graphql": "extend type User { email: String }",
+	})
+
+	for _, file := range []string{"wrapped.json", "bare.json"} {
+		t.Run(file, func(t *testing.T) {
+			schema, err := LoadSchema(filepath.Join(dir, file), filepath.Join(dir, "extend.graphql"))
+			if err != nil {
+				t.Fatalf("LoadSchema() error = %v", err)
+			}
+
+			if schema.Query == nil || schema.Query.Fields.ForName("users").Type.String() != "[User]!" {
+				t.Errorf("LoadSchema() did not load Query.users")
+			}
+
+			if schema.Query.Fields.ForName("users").Arguments.ForName("role").DefaultValue.Raw != "ADMIN" {
+				t.Errorf("LoadSchema() did not load the default value of Query.users(role)")
+			}
+
+			if schema.Types["User"].Fields.ForName("name").Directives.ForName("deprecated") == nil {
+				t.Errorf("LoadSchema() did not mark User.name as deprecated")
+			}
+
+			if schema.Types["User"].Fields.ForName("email") == nil {
+				t.Errorf("LoadSchema() did not merge the SDL extension")
+			}
+		})
+	}
+
+	_, err := LoadSchema(filepath.Join(dir, "invalid.json"))
+	if err == nil || !strings.Contains(err.Error(), "invalid.json") {
+		t.Errorf("LoadSchema() error = %v, expected it to name invalid.json", err)
+	}
+}
+
+// TestLoadSchemaFromURL tests running the introspection query against an endpoint
+func TestLoadSchemaFromURL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "IntrospectionQuery") {
+			t.Errorf("request body = %s, expected an introspection query", body)
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"__schema":` + testIntrospectionSchema + `}}`))
+	}))
+	defer server.Close()
+
+	schema, err := LoadSchemaWithOptions(SchemaLoadOptions{
+		Headers: map[string]map[string]string{
+			server.URL: {"Authorization": "Bearer token"},
+		},
+	}, server.URL)
+	if err != nil {
+		t.Fatalf("LoadSchemaWithOptions() error = %v", err)
+	}
+
+	if schema.Types["User"] == nil {
+		t.Errorf("LoadSchemaWithOptions() is missing type User")
+	}
+
+	_, err = LoadSchema(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("LoadSchema() error = %v, expected an unauthorized error", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("server received %d requests, expected 2", requests)
+	}
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"os"
+	"path/filepath"
+)
+
+type DestinationStatus string
+
+const (
+	DestinationCreated   DestinationStatus = "created"
+	DestinationUpdated   DestinationStatus = "updated"
+	DestinationUnchanged DestinationStatus = "unchanged"
+	// DestinationSkipped is reported for existing files that may not be overwritten
+	DestinationSkipped DestinationStatus = "skipped"
+	// DestinationMissing and DestinationStale are only reported in check mode, where nothing is written
+	DestinationMissing DestinationStatus = "missing"
+	DestinationStale   DestinationStatus = "out of date"
+)
+
+/*
+DestinationResult describes what happened to a generated file
+*/
+type DestinationResult struct {
+	ProjectRoot string
+	ConfigFile  string
+	Destination string
+	Status      DestinationStatus
+	// Diff is a unified diff from the file on disk to the generated output, only set in check mode when requested
+	Diff string
+}
+
+/*
+ExecuteResult holds the outcome of every generation task
+*/
+type ExecuteResult struct {
+	Destinations []DestinationResult
+	Errors       []*TaskError
+	// Warnings holds the document validation errors of projects that ignore them
+	Warnings []*TaskError
+}
+
+/*
+CountStatus returns the number of destinations with the given status
+*/
+func (r *ExecuteResult) CountStatus(status DestinationStatus) int {
+	count := 0
+	for _, destination := range r.Destinations {
+		if destination.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+/*
+OutOfDate returns the destinations that check mode found to be missing or stale
+*/
+func (r *ExecuteResult) OutOfDate() []DestinationResult {
+	var outOfDate []DestinationResult
+	for _, destination := range r.Destinations {
+		if destination.Status == DestinationMissing || destination.Status == DestinationStale {
+			outOfDate = append(outOfDate, destination)
+		}
+	}
+
+	return outOfDate
+}
+
+/*
+checkOutput compares content to destinationFile without writing it, a diff is created if withDiff is set
+*/
+func checkOutput(destinationFile string, destination string, content []byte, withDiff bool) (DestinationStatus, string, error) {
+	status := DestinationStale
+
+	existingContent, err := os.ReadFile(destinationFile)
+	if errors.Is(err, os.ErrNotExist) {
+		status = DestinationMissing
+	} else if err != nil {
+		return "", "", err
+	}
+
+	if status == DestinationStale && bytes.Equal(existingContent, content) {
+		return DestinationUnchanged, "", nil
+	}
+
+	if !withDiff {
+		return status, "", nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(existingContent)),
+		B:        difflib.SplitLines(string(content)),
+		FromFile: "a/" + destination,
+		ToFile:   "b/" + destination,
+		Context:  3,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return status, diff, nil
+}
+
+/*
+writeOutput writes content to destinationFile, unless the file already has that content. Leaving unchanged files
+alone keeps their modification time, so file watchers are not triggered.
+*/
+func writeOutput(destinationFile string, content []byte) (DestinationStatus, error) {
+	status := DestinationCreated
+	mode := os.FileMode(0644)
+
+	existingContent, err := os.ReadFile(destinationFile)
+	if err == nil {
+		if bytes.Equal(existingContent, content) {
+			return DestinationUnchanged, nil
+		}
+
+		status = DestinationUpdated
+		if info, err := os.Stat(destinationFile); err == nil {
+			mode = info.Mode().Perm()
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	// ensure output dir exists
+	if err := EnsureDir(destinationFile); err != nil {
+		return "", err
+	}
+
+	if err := writeFileAtomic(destinationFile, content, mode); err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
+
+/*
+writeFileAtomic writes content to a temporary file next to file and renames it into place, so file is never left
+partially written. The temporary file is removed if anything fails.
+*/
+func writeFileAtomic(file string, content []byte, mode os.FileMode) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, writeErr := tempFile.Write(content)
+	closeErr := tempFile.Close()
+	if err := errors.Join(writeErr, closeErr, os.Chmod(tempFile.Name(), mode)); err != nil {
+		_ = os.Remove(tempFile.Name())
+		return err
+	}
+
+	if err := os.Rename(tempFile.Name(), file); err != nil {
+		_ = os.Remove(tempFile.Name())
+		return err
+	}
+
+	return nil
+}
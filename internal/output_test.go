@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWriteFileAtomic tests that output is renamed into place and no temporary files are left behind
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "out.ts")
+
+	if err := writeFileAtomic(file, []byte("content"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	if content, _ := os.ReadFile(file); string(content) != "content" {
+		t.Errorf("content = %q, expected %q", content, "content")
+	}
+	if info, _ := os.Stat(file); info.Mode().Perm() != 0640 {
+		t.Errorf("mode = %v, expected %v", info.Mode().Perm(), os.FileMode(0640))
+	}
+
+	// renaming onto a directory that is not empty fails
+	blocked := filepath.Join(dir, "blocked.ts")
+	if err := os.MkdirAll(filepath.Join(blocked, "child"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFileAtomic(blocked, []byte("content"), 0644); err == nil {
+		t.Errorf("writing onto a directory succeeded, expected an error")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("directory contains %d entries, expected the temporary file to be removed", len(entries))
+	}
+}
+
+// TestWriteOutputKeepsMode tests that updating a file keeps its permissions
+func TestWriteOutputKeepsMode(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "nested", "out.ts")
+
+	if status, err := writeOutput(file, []byte("first")); err != nil || status != DestinationCreated {
+		t.Fatalf("writeOutput() = %s, %v, expected %s", status, err, DestinationCreated)
+	}
+	if err := os.Chmod(file, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if status, err := writeOutput(file, []byte("second")); err != nil || status != DestinationUpdated {
+		t.Fatalf("writeOutput() = %s, %v, expected %s", status, err, DestinationUpdated)
+	}
+	if info, _ := os.Stat(file); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, expected %v", info.Mode().Perm(), os.FileMode(0600))
+	}
+}
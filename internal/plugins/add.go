@@ -0,0 +1,71 @@
+package plugins
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+/*
+addOptions are the options of the add plugin
+*/
+type addOptions struct {
+	// Content is the text to write, a list is written line by line
+	Content addContent `json:"content"`
+}
+
+/*
+addContent is text given as a string or as a list of lines
+*/
+type addContent string
+
+func (c *addContent) UnmarshalJSON(data []byte) error {
+	var content string
+	if err := json.Unmarshal(data, &content); err == nil {
+		*c = addContent(content)
+		return nil
+	}
+
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err != nil {
+		return errors.New("content must be a string or a list of strings")
+	}
+
+	*c = addContent(strings.Join(lines, "\n"))
+	return nil
+}
+
+func init() {
+	registerPresetPlugin(addPlugin{})
+}
+
+/*
+addPlugin writes the text it is configured with, like @graphql-codegen/add. Presets write the files that are not
+generated from the schema with it.
+*/
+type addPlugin struct{}
+
+func (addPlugin) Name() string {
+	return "add"
+}
+
+func (addPlugin) DecodeOptions(config map[string]interface{}) (interface{}, error) {
+	options := addOptions{}
+	err := decodeOptions(config, &options)
+	return options, err
+}
+
+func (addPlugin) OutputExtensions() []string {
+	return nil
+}
+
+func (addPlugin) Generate(task PluginTask, output io.Writer) error {
+	options, _ := task.Options.(addOptions)
+	if options.Content == "" {
+		return nil
+	}
+
+	_, err := io.WriteString(output, string(options.Content)+"\n")
+	return err
+}
@@ -1,6 +1,7 @@
 package plugins
 
 import (
+	"fmt"
 	"github.com/iancoleman/strcase"
 	"strings"
 )
@@ -12,3 +13,32 @@ func ToUpper(input string) string {
 func ToCamel(input string) string {
 	return strcase.ToCamel(input)
 }
+
+var caseConverters = map[string]func(string) string{
+	"keep":         func(input string) string { return input },
+	"camelCase":    strcase.ToLowerCamel,
+	"pascalCase":   strcase.ToCamel,
+	"constantCase": strcase.ToScreamingSnake,
+	"snakeCase":    strcase.ToSnake,
+	"paramCase":    strcase.ToKebab,
+	"upperCase":    strings.ToUpper,
+	"lowerCase":    strings.ToLower,
+}
+
+/*
+ConvertCase converts a name with a naming convention written like upstream's, such as change-case-all#pascalCase.
+The module before the # is optional, and keep leaves names unchanged.
+*/
+func ConvertCase(input string, convention string) (string, error) {
+	_, function, found := strings.Cut(convention, "#")
+	if !found {
+		function = convention
+	}
+
+	converter, ok := caseConverters[function]
+	if !ok {
+		return "", fmt.Errorf("unknown naming convention %s", convention)
+	}
+
+	return converter(input), nil
+}
@@ -0,0 +1,102 @@
+package plugins
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+	"slices"
+
+	// the validation rules register themselves
+	_ "github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+/*
+skippedRules are the validation rules upstream leaves out for documents: fragments and variables may be used by
+other documents, and client directives are unknown to the schema
+*/
+var skippedRules = []string{"NoUnusedFragments", "NoUnusedVariables", "KnownDirectives"}
+
+/*
+ValidateDocuments validates documents against a schema like upstream does. Each document is validated on its own,
+with the fragments of the other documents available to it, and operation and fragment names must be unique across
+documents.
+*/
+func ValidateDocuments(schema *ast.Schema, documents []*Document) gqlerror.List {
+	fragments := AllFragments(documents)
+
+	errs := validateUniqueNames(documents)
+	seen := make(map[string]bool)
+	for _, document := range documents {
+		validated := &ast.QueryDocument{
+			Operations: document.Document.Operations,
+			Fragments:  slices.Clone(document.Document.Fragments),
+		}
+		for _, fragment := range fragments {
+			if validated.Fragments.ForName(fragment.Name) == nil {
+				validated.Fragments = append(validated.Fragments, fragment)
+			}
+		}
+
+		for _, err := range validator.Validate(schema, validated) {
+			// errors in fragments of other documents are reported once
+			if slices.Contains(skippedRules, err.Rule) || seen[err.Error()] {
+				continue
+			}
+
+			seen[err.Error()] = true
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+/*
+validateUniqueNames reports operations and fragments named like one in an earlier document, at the later definition.
+Names within a document are checked by the validation rules, and the same document written in many places is
+defined once.
+*/
+func validateUniqueNames(documents []*Document) gqlerror.List {
+	var errs gqlerror.List
+	operations := make(map[string]*Document)
+	fragments := make(map[string]*Document)
+	for _, document := range documents {
+		for _, operation := range document.Document.Operations {
+			if operation.Name == "" {
+				continue
+			}
+
+			if first, ok := operations[operation.Name]; ok && first != document && first.Raw != document.Raw {
+				err := gqlerror.ErrorPosf(operation.Position, `There can be only one operation named "%s".`, operation.Name)
+				err.Rule = "UniqueOperationNames"
+				errs = append(errs, err)
+			} else if !ok {
+				operations[operation.Name] = document
+			}
+		}
+
+		for _, fragment := range document.Document.Fragments {
+			if first, ok := fragments[fragment.Name]; ok && first != document && first.Raw != document.Raw {
+				err := gqlerror.ErrorPosf(fragment.Position, `There can be only one fragment named "%s".`, fragment.Name)
+				err.Rule = "UniqueFragmentNames"
+				errs = append(errs, err)
+			} else if !ok {
+				fragments[fragment.Name] = document
+			}
+		}
+	}
+
+	return errs
+}
+
+/*
+AllFragments returns the fragments of every document, in document order
+*/
+func AllFragments(documents []*Document) ast.FragmentDefinitionList {
+	var fragments ast.FragmentDefinitionList
+	for _, document := range documents {
+		fragments = append(fragments, document.Document.Fragments...)
+	}
+
+	return fragments
+}
@@ -0,0 +1,129 @@
+package plugins
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"io"
+	"slices"
+	"strings"
+)
+
+/*
+gqlTagOperationsOptions are the options of the gql-tag-operations plugin
+*/
+type gqlTagOperationsOptions struct {
+	// GqlTagName is the name of the function documents are written with, graphql by default
+	GqlTagName string `json:"gqlTagName"`
+}
+
+func init() {
+	registerPresetPlugin(gqlTagOperationsPlugin{})
+}
+
+/*
+gqlTagOperationsPlugin writes a function that returns the typed document of every document it is called with, like
+@graphql-codegen/gql-tag-operations. It writes gql.ts of the client preset, the documents are imported from the
+graphql.ts next to it.
+*/
+type gqlTagOperationsPlugin struct{}
+
+func (gqlTagOperationsPlugin) Name() string {
+	return "gql-tag-operations"
+}
+
+func (gqlTagOperationsPlugin) DecodeOptions(config map[string]interface{}) (interface{}, error) {
+	options := gqlTagOperationsOptions{}
+	err := decodeOptions(config, &options)
+	return options, err
+}
+
+func (gqlTagOperationsPlugin) OutputExtensions() []string {
+	return nil
+}
+
+func (gqlTagOperationsPlugin) Generate(task PluginTask, output io.Writer) error {
+	options, _ := task.Options.(gqlTagOperationsOptions)
+
+	converted := strings.Builder{}
+	convertGqlTagOperations(task.Documents, &converted, options)
+
+	_, err := io.WriteString(output, converted.String())
+	return err
+}
+
+/*
+convertGqlTagOperations writes an overload of the gql function for every document, keyed by the document as written.
+A document refers to the typed document of its first operation or fragment, anonymous operations are skipped.
+*/
+func convertGqlTagOperations(documents []*Document, output *strings.Builder, options gqlTagOperationsOptions) {
+	gqlTagName := options.GqlTagName
+	if gqlTagName == "" {
+		gqlTagName = "graphql"
+	}
+
+	var sources []string
+	var typedDocuments []string
+	for _, document := range documents {
+		typedDocument := ""
+		for _, definition := range documentDefinitions(document.Document) {
+			if operation, ok := definition.(*ast.OperationDefinition); !ok || operation.Name != "" {
+				typedDocument = TypedDocumentName(definition)
+				break
+			}
+		}
+
+		// the same document written in many places is the same key
+		if typedDocument == "" || slices.Contains(sources, document.Raw) {
+			continue
+		}
+
+		sources = append(sources, document.Raw)
+		typedDocuments = append(typedDocuments, typedDocument)
+	}
+
+	output.WriteString("import * as types from './graphql';\n")
+	output.WriteString("import { TypedDocumentNode as DocumentNode } from '@graphql-typed-document-node/core';\n\n")
+
+	output.WriteString(`/**
+ * Map of all GraphQL operations in the project.
+ *
+ * This map has several performance disadvantages:
+ * 1. It is not tree-shakeable, so it will include all operations in the project.
+ * 2. It is not minifiable, so the string of a GraphQL query will be multiple times inside the bundle.
+ * 3. It does not support dead code elimination, so it will add unused operations.
+ */
+`)
+	output.WriteString("const documents = {\n")
+	for i, source := range sources {
+		output.WriteString("\t")
+		writeJSONString(output, source)
+		output.WriteString(": types." + typedDocuments[i] + ",\n")
+	}
+	output.WriteString("};\n\n")
+
+	output.WriteString(`/**
+ * The ` + gqlTagName + ` function is used to parse GraphQL queries into a document that can be used by GraphQL clients.
+ *
+ *
+ * @example
+ * ` + "```ts" + `
+ * const query = ` + gqlTagName + "(`query GetUser($id: ID!) { user(id: $id) { name } }`);" + `
+ * ` + "```" + `
+ *
+ * The query argument is unknown!
+ * Please regenerate the types.
+ */
+export function ` + gqlTagName + `(source: string): unknown;
+`)
+
+	for _, source := range sources {
+		key := strings.Builder{}
+		writeJSONString(&key, source)
+
+		output.WriteString("\n/**\n * The " + gqlTagName + " function is used to parse GraphQL queries into a document that can be used by GraphQL clients.\n */\n")
+		output.WriteString("export function " + gqlTagName + "(source: " + key.String() + "): (typeof documents)[" + key.String() + "];\n")
+	}
+
+	output.WriteString("\nexport function " + gqlTagName + "(source: string) {\n\treturn (documents as any)[source] ?? {};\n}\n\n")
+	output.WriteString("export type DocumentType<TDocumentNode extends DocumentNode<any, any>> = " +
+		"TDocumentNode extends DocumentNode<infer TType, any> ? TType : never;\n")
+}
@@ -0,0 +1,33 @@
+package plugins
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestConvertGqlTagOperations tests that documents are keyed as written and refer to their first named definition
+func TestConvertGqlTagOperations(t *testing.T) {
+	documents := testDocuments(t,
+		"\n  query GetUser { user(id: 1) { ...UserFields } }\n",
+		`{ user(id: 1) { id } }`,
+		`fragment UserFields on User { id } query Unused { user(id: 1) { id } }`,
+		"\n  query GetUser { user(id: 1) { ...UserFields } }\n",
+	)
+
+	output := strings.Builder{}
+	convertGqlTagOperations(documents, &output, gqlTagOperationsOptions{GqlTagName: "gql"})
+
+	expected := "const documents = {\n" +
+		"\t\"\\n  query GetUser { user(id: 1) { ...UserFields } }\\n\": types.GetUserDocument,\n" +
+		"\t\"fragment UserFields on User { id } query Unused { user(id: 1) { id } }\": types.UserFieldsFragmentDoc,\n" +
+		"};\n"
+	if !strings.Contains(output.String(), expected) {
+		t.Errorf("convertGqlTagOperations() = %s, expected it to contain %s", output.String(), expected)
+	}
+
+	expected = "export function gql(source: \"\\n  query GetUser { user(id: 1) { ...UserFields } }\\n\"): " +
+		"(typeof documents)[\"\\n  query GetUser { user(id: 1) { ...UserFields } }\\n\"];\n"
+	if strings.Count(output.String(), expected) != 1 {
+		t.Errorf("convertGqlTagOperations() = %s, expected it to contain %s once", output.String(), expected)
+	}
+}
@@ -1,5 +1,597 @@
 package plugins
 
-func (p *PluginTask) Introspect() {
-	p.Output.WriteString("{\"message\":\"introspection plugin is not implemented\"}\n")
+import (
+	"fmt"
+	"github.com/vektah/gqlparser/v2/ast"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+IntrospectionOptions are the options of the introspection plugin, named like upstream's
+*/
+type IntrospectionOptions struct {
+	// Minify writes the result without whitespace
+	Minify bool `json:"minify"`
+	// Descriptions includes descriptions, on by default
+	Descriptions *bool `json:"descriptions"`
+	// SchemaDescription includes the description of the schema
+	SchemaDescription bool `json:"schemaDescription"`
+	// SpecifiedByUrl includes the specifiedByURL of scalars
+	SpecifiedByUrl bool `json:"specifiedByUrl"`
+	// DirectiveIsRepeatable includes whether directives are repeatable, on by default
+	DirectiveIsRepeatable *bool `json:"directiveIsRepeatable"`
+}
+
+func init() {
+	Register(introspectionPlugin{})
+}
+
+/*
+introspectionPlugin writes the result of the standard introspection query for the schema as JSON, formatted like
+@graphql-codegen/introspection formats it
+*/
+type introspectionPlugin struct{}
+
+func (introspectionPlugin) Name() string {
+	return "introspection"
+}
+
+func (introspectionPlugin) DecodeOptions(config map[string]interface{}) (interface{}, error) {
+	options := IntrospectionOptions{}
+	err := decodeOptions(config, &options)
+	return options, err
+}
+
+func (introspectionPlugin) OutputExtensions() []string {
+	return []string{".json"}
+}
+
+func (introspectionPlugin) Generate(task PluginTask, output io.Writer) error {
+	options, _ := task.Options.(IntrospectionOptions)
+
+	indent := "  "
+	if options.Minify {
+		indent = ""
+	}
+
+	result := strings.Builder{}
+	writeJSON(&result, IntrospectionResult(task.Schema, task.Sort, task.SchemaFiles, options), indent)
+
+	_, err := io.WriteString(output, result.String())
+	return err
+}
+
+/*
+IntrospectionResult builds the result graphql-js returns for introspectionFromSchema, including the built-in
+scalars, introspection types and directives of graphql-js. Unless sortAlphabetically is set, types are in definition
+order, with the schema files in sourceOrder.
+*/
+func IntrospectionResult(schema *ast.Schema, sortAlphabetically bool, sourceOrder []string, options IntrospectionOptions) jsonObject {
+	i := newIntrospector(schema, sortAlphabetically, sourceOrder, options)
+
+	types := make([]interface{}, 0, len(i.types))
+	for _, definition := range i.types {
+		types = append(types, i.fullType(definition))
+	}
+
+	directives := make([]interface{}, 0, len(i.directives))
+	for _, directive := range i.directives {
+		directives = append(directives, i.directive(directive))
+	}
+
+	var result jsonObject
+	if options.SchemaDescription {
+		result = append(result, jsonField{"description", nullableString(schema.Description)})
+	}
+	result = append(result,
+		jsonField{"queryType", rootType(schema.Query)},
+		jsonField{"mutationType", rootType(schema.Mutation)},
+		jsonField{"subscriptionType", rootType(schema.Subscription)},
+		jsonField{"types", types},
+		jsonField{"directives", directives},
+	)
+
+	return jsonObject{{"__schema", result}}
+}
+
+/*
+introspector holds the types and directives of a schema in the order graphql-js would introspect them
+*/
+type introspector struct {
+	options    IntrospectionOptions
+	types      []*ast.Definition
+	typesByKey map[string]*ast.Definition
+	directives []*ast.DirectiveDefinition
+}
+
+func newIntrospector(schema *ast.Schema, sortAlphabetically bool, sourceOrder []string, options IntrospectionOptions) *introspector {
+	specified := getSpecified()
+	i := &introspector{
+		options:    options,
+		typesByKey: make(map[string]*ast.Definition),
+	}
+
+	// built-in types are described like graphql-js describes them
+	lookup := func(name string) *ast.Definition {
+		if definition, ok := specified.Types[name]; ok {
+			return definition
+		}
+		return schema.Types[name]
+	}
+
+	var definitions []*ast.Definition
+	for _, definition := range OrderedDefinitions(schema, false, sourceOrder) {
+		if !definition.BuiltIn && !strings.HasPrefix(definition.Name, "__") {
+			definitions = append(definitions, definition)
+		}
+	}
+
+	i.directives = orderedDirectives(schema, specified, sourceOrder)
+
+	// types are collected like the GraphQLSchema constructor does, which adds referenced built-in types after the
+	// first type that references them
+	collected := make(map[string]bool)
+	for _, definition := range definitions {
+		collected[definition.Name] = true
+	}
+
+	var collect func(name string)
+	collectType := func(definition *ast.Definition) {
+		for _, name := range definition.Interfaces {
+			collect(name)
+		}
+		for _, name := range definition.Types {
+			collect(name)
+		}
+		for _, field := range definition.Fields {
+			if strings.HasPrefix(field.Name, "__") {
+				continue
+			}
+
+			collect(field.Type.Name())
+			for _, argument := range field.Arguments {
+				collect(argument.Type.Name())
+			}
+		}
+	}
+	collect = func(name string) {
+		definition := lookup(name)
+		if collected[name] || definition == nil {
+			return
+		}
+
+		collected[name] = true
+		i.types = append(i.types, definition)
+		collectType(definition)
+	}
+
+	for _, definition := range definitions {
+		i.types = append(i.types, definition)
+		collectType(definition)
+	}
+	for _, directive := range i.directives {
+		for _, argument := range directive.Arguments {
+			collect(argument.Type.Name())
+		}
+	}
+	collect("__Schema")
+
+	if sortAlphabetically {
+		slices.SortStableFunc(i.types, func(a, b *ast.Definition) int {
+			return NaturalCompare(a.Name, b.Name)
+		})
+
+		// introspection types keep their order, like in graphql-js
+		for index, definition := range i.types {
+			if !definition.BuiltIn {
+				i.types[index] = sortDefinition(definition)
+			}
+		}
+
+		slices.SortStableFunc(i.directives, func(a, b *ast.DirectiveDefinition) int {
+			return NaturalCompare(a.Name, b.Name)
+		})
+		for index, directive := range i.directives {
+			sortedDirective := *directive
+			sortedDirective.Arguments = slices.Clone(directive.Arguments)
+			slices.SortStableFunc(sortedDirective.Arguments, func(a, b *ast.ArgumentDefinition) int {
+				return NaturalCompare(a.Name, b.Name)
+			})
+			i.directives[index] = &sortedDirective
+		}
+	}
+
+	for _, definition := range i.types {
+		i.typesByKey[definition.Name] = definition
+	}
+
+	return i
+}
+
+/*
+orderedDirectives returns the directives of the schema in definition order, followed by the directives graphql-js
+specifies unless the schema defines them
+*/
+func orderedDirectives(schema *ast.Schema, specified specifiedDefinitions, sourceOrder []string) []*ast.DirectiveDefinition {
+	var directives []*ast.DirectiveDefinition
+	for _, directive := range schema.Directives {
+		if !slices.Contains(prelude, directive.Name) {
+			directives = append(directives, directive)
+		}
+	}
+
+	slices.SortFunc(directives, func(a, b *ast.DirectiveDefinition) int {
+		if a.Position != nil && b.Position != nil {
+			if order := compareSources(a.Position.Src, b.Position.Src, sourceOrder); order != 0 {
+				return order
+			}
+			if a.Position.Start != b.Position.Start {
+				return a.Position.Start - b.Position.Start
+			}
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for _, directive := range specified.Directives {
+		if !slices.ContainsFunc(directives, func(d *ast.DirectiveDefinition) bool { return d.Name == directive.Name }) {
+			directives = append(directives, directive)
+		}
+	}
+
+	return directives
+}
+
+func (i *introspector) fullType(definition *ast.Definition) jsonObject {
+	object := jsonObject{
+		{"kind", string(definition.Kind)},
+		{"name", definition.Name},
+	}
+	object = i.appendDescription(object, definition.Description)
+
+	if i.options.SpecifiedByUrl {
+		var specifiedByURL interface{}
+		if definition.Kind == ast.Scalar {
+			if directive := definition.Directives.ForName("specifiedBy"); directive != nil {
+				specifiedByURL = directive.Arguments.ForName("url").Value.Raw
+			}
+		}
+		object = append(object, jsonField{"specifiedByURL", specifiedByURL})
+	}
+
+	var isOneOf interface{}
+	if definition.Kind == ast.InputObject {
+		isOneOf = definition.Directives.ForName("oneOf") != nil
+	}
+	object = append(object, jsonField{"isOneOf", isOneOf})
+
+	var fields, inputFields, interfaces, enumValues, possibleTypes interface{}
+	switch definition.Kind {
+	case ast.Object, ast.Interface:
+		fieldList := []interface{}{}
+		for _, field := range definition.Fields {
+			if !strings.HasPrefix(field.Name, "__") {
+				fieldList = append(fieldList, i.field(field))
+			}
+		}
+		fields = fieldList
+
+		interfaceList := []interface{}{}
+		for _, name := range definition.Interfaces {
+			interfaceList = append(interfaceList, i.typeRef(ast.NamedType(name, nil)))
+		}
+		interfaces = interfaceList
+	case ast.InputObject:
+		inputFieldList := []interface{}{}
+		for _, field := range definition.Fields {
+			inputFieldList = append(inputFieldList, i.inputValue(field.Name, field.Description, field.Type, field.DefaultValue, field.Directives))
+		}
+		inputFields = inputFieldList
+	case ast.Enum:
+		enumValueList := []interface{}{}
+		for _, enumValue := range definition.EnumValues {
+			enumValueList = append(enumValueList, i.enumValue(enumValue))
+		}
+		enumValues = enumValueList
+	}
+
+	switch definition.Kind {
+	case ast.Interface:
+		// implementations are listed in the order of the types, like graphql-js does
+		possibleTypeList := []interface{}{}
+		for _, candidate := range i.types {
+			if candidate.Kind == ast.Object && slices.Contains(candidate.Interfaces, definition.Name) {
+				possibleTypeList = append(possibleTypeList, i.typeRef(ast.NamedType(candidate.Name, nil)))
+			}
+		}
+		possibleTypes = possibleTypeList
+	case ast.Union:
+		possibleTypeList := []interface{}{}
+		for _, name := range definition.Types {
+			possibleTypeList = append(possibleTypeList, i.typeRef(ast.NamedType(name, nil)))
+		}
+		possibleTypes = possibleTypeList
+	}
+
+	return append(object,
+		jsonField{"fields", fields},
+		jsonField{"inputFields", inputFields},
+		jsonField{"interfaces", interfaces},
+		jsonField{"enumValues", enumValues},
+		jsonField{"possibleTypes", possibleTypes},
+	)
+}
+
+func (i *introspector) field(field *ast.FieldDefinition) jsonObject {
+	object := jsonObject{{"name", field.Name}}
+	object = i.appendDescription(object, field.Description)
+
+	isDeprecated, deprecationReason := deprecation(field.Directives)
+
+	return append(object,
+		jsonField{"args", i.arguments(field.Arguments)},
+		jsonField{"type", i.typeRef(field.Type)},
+		jsonField{"isDeprecated", isDeprecated},
+		jsonField{"deprecationReason", deprecationReason},
+	)
+}
+
+func (i *introspector) arguments(arguments ast.ArgumentDefinitionList) []interface{} {
+	argumentList := []interface{}{}
+	for _, argument := range arguments {
+		argumentList = append(argumentList, i.inputValue(argument.Name, argument.Description, argument.Type, argument.DefaultValue, argument.Directives))
+	}
+
+	return argumentList
+}
+
+func (i *introspector) inputValue(name string, description string, valueType *ast.Type, defaultValue *ast.Value, directives ast.DirectiveList) jsonObject {
+	object := jsonObject{{"name", name}}
+	object = i.appendDescription(object, description)
+
+	var printedDefault interface{}
+	if defaultValue != nil {
+		printedDefault = i.printValue(defaultValue, valueType)
+	}
+
+	isDeprecated, deprecationReason := deprecation(directives)
+
+	return append(object,
+		jsonField{"type", i.typeRef(valueType)},
+		jsonField{"defaultValue", printedDefault},
+		jsonField{"isDeprecated", isDeprecated},
+		jsonField{"deprecationReason", deprecationReason},
+	)
+}
+
+func (i *introspector) enumValue(enumValue *ast.EnumValueDefinition) jsonObject {
+	object := jsonObject{{"name", enumValue.Name}}
+	object = i.appendDescription(object, enumValue.Description)
+
+	isDeprecated, deprecationReason := deprecation(enumValue.Directives)
+
+	return append(object,
+		jsonField{"isDeprecated", isDeprecated},
+		jsonField{"deprecationReason", deprecationReason},
+	)
+}
+
+func (i *introspector) directive(directive *ast.DirectiveDefinition) jsonObject {
+	object := jsonObject{{"name", directive.Name}}
+	object = i.appendDescription(object, directive.Description)
+
+	if i.options.DirectiveIsRepeatable == nil || *i.options.DirectiveIsRepeatable {
+		object = append(object, jsonField{"isRepeatable", directive.IsRepeatable})
+	}
+
+	locations := make([]interface{}, 0, len(directive.Locations))
+	for _, location := range directive.Locations {
+		locations = append(locations, string(location))
+	}
+
+	return append(object,
+		jsonField{"locations", locations},
+		jsonField{"args", i.arguments(directive.Arguments)},
+	)
+}
+
+func (i *introspector) appendDescription(object jsonObject, description string) jsonObject {
+	if i.options.Descriptions != nil && !*i.options.Descriptions {
+		return object
+	}
+
+	return append(object, jsonField{"description", nullableString(description)})
+}
+
+func (i *introspector) typeRef(fieldType *ast.Type) jsonObject {
+	if fieldType.NonNull {
+		nullableType := *fieldType
+		nullableType.NonNull = false
+
+		return jsonObject{{"kind", "NON_NULL"}, {"name", nil}, {"ofType", i.typeRef(&nullableType)}}
+	}
+
+	if fieldType.Elem != nil {
+		return jsonObject{{"kind", "LIST"}, {"name", nil}, {"ofType", i.typeRef(fieldType.Elem)}}
+	}
+
+	kind := ast.Scalar
+	if definition, ok := i.typesByKey[fieldType.NamedType]; ok {
+		kind = definition.Kind
+	}
+
+	return jsonObject{{"kind", string(kind)}, {"name", fieldType.NamedType}, {"ofType", nil}}
+}
+
+var integerPattern = regexp.MustCompile(`^-?(?:0|[1-9][0-9]*)$`)
+
+/*
+printValue prints a default value like graphql-js does after coercing it to valueType, so defaults of input fields
+are filled in and numbers are normalized
+*/
+func (i *introspector) printValue(value *ast.Value, valueType *ast.Type) string {
+	if value.Kind == ast.NullValue {
+		return "null"
+	}
+
+	if valueType.Elem != nil {
+		if value.Kind != ast.ListValue {
+			return i.printValue(value, valueType.Elem)
+		}
+
+		items := make([]string, 0, len(value.Children))
+		for _, child := range value.Children {
+			items = append(items, i.printValue(child.Value, valueType.Elem))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+
+	definition := i.typesByKey[valueType.NamedType]
+	if definition == nil {
+		return printUntypedValue(value)
+	}
+
+	switch {
+	case definition.Kind == ast.InputObject && value.Kind == ast.ObjectValue:
+		var fields []string
+		for _, field := range definition.Fields {
+			if child := value.Children.ForName(field.Name); child != nil {
+				fields = append(fields, field.Name+": "+i.printValue(child, field.Type))
+			} else if field.DefaultValue != nil {
+				fields = append(fields, field.Name+": "+i.printValue(field.DefaultValue, field.Type))
+			}
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case definition.Kind == ast.Enum:
+		return value.Raw
+	case definition.Name == "String" && isStringValue(value):
+		return printString(value.Raw)
+	case definition.Name == "ID" && (isStringValue(value) || value.Kind == ast.IntValue):
+		if integerPattern.MatchString(value.Raw) {
+			return value.Raw
+		}
+		return printString(value.Raw)
+	case definition.Name == "Float" && (value.Kind == ast.FloatValue || value.Kind == ast.IntValue):
+		return printNumber(value.Raw)
+	}
+
+	return printUntypedValue(value)
+}
+
+func printUntypedValue(value *ast.Value) string {
+	switch value.Kind {
+	case ast.StringValue, ast.BlockValue:
+		return printString(value.Raw)
+	case ast.FloatValue:
+		return printNumber(value.Raw)
+	case ast.ListValue:
+		items := make([]string, 0, len(value.Children))
+		for _, child := range value.Children {
+			items = append(items, printUntypedValue(child.Value))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case ast.ObjectValue:
+		fields := make([]string, 0, len(value.Children))
+		for _, child := range value.Children {
+			fields = append(fields, child.Name+": "+printUntypedValue(child.Value))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return value.Raw
+	}
+}
+
+func isStringValue(value *ast.Value) bool {
+	return value.Kind == ast.StringValue || value.Kind == ast.BlockValue
+}
+
+/*
+printString quotes a string like the printString of graphql-js
+*/
+func printString(value string) string {
+	output := strings.Builder{}
+	output.WriteByte('"')
+
+	for _, character := range value {
+		switch {
+		case character == '"':
+			output.WriteString(`\"`)
+		case character == '\\':
+			output.WriteString(`\\`)
+		case character == '\b':
+			output.WriteString(`\b`)
+		case character == '\t':
+			output.WriteString(`\t`)
+		case character == '\n':
+			output.WriteString(`\n`)
+		case character == '\f':
+			output.WriteString(`\f`)
+		case character == '\r':
+			output.WriteString(`\r`)
+		case character < 0x20 || (character >= 0x7f && character <= 0x9f):
+			output.WriteString(fmt.Sprintf(`\u%04X`, character))
+		default:
+			output.WriteRune(character)
+		}
+	}
+
+	output.WriteByte('"')
+	return output.String()
+}
+
+/*
+printNumber formats a number like JavaScript converts numbers to strings
+*/
+func printNumber(raw string) string {
+	number, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return raw
+	}
+
+	if number == 0 {
+		return "0"
+	}
+
+	if math.Abs(number) >= 1e-6 && math.Abs(number) < 1e21 {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(number, 'e', -1, 64), "e")
+	sign, digits := exponent[:1], strings.TrimLeft(exponent[1:], "0")
+
+	return mantissa + "e" + sign + digits
+}
+
+func deprecation(directives ast.DirectiveList) (bool, interface{}) {
+	directive := directives.ForName("deprecated")
+	if directive == nil {
+		return false, nil
+	}
+
+	if reason := directive.Arguments.ForName("reason"); reason != nil && reason.Value.Kind != ast.NullValue {
+		return true, reason.Value.Raw
+	}
+
+	return true, "No longer supported"
+}
+
+func rootType(definition *ast.Definition) interface{} {
+	if definition == nil {
+		return nil
+	}
+
+	return jsonObject{{"name", definition.Name}}
+}
+
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+
+	return value
 }
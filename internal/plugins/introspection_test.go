@@ -0,0 +1,170 @@
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testIntrospectionSchema = `
+type Query {
+	user(id: ID!, filter: Filter = {name: "<x>"}): User
+	search(limit: Int = 10, ratio: Float = 1.0): [User!]! @deprecated(reason: "Use user")
+}
+
+"A user"
+type User {
+	name: String
+	role: Role
+}
+
+enum Role {
+	ADMIN
+	GUEST @deprecated
+}
+
+input Filter {
+	name: String
+	age: Int = 3
+}
+
+directive @auth(role: Role = ADMIN) repeatable on FIELD_DEFINITION
+`
+
+func introspect(t *testing.T, sortAlphabetically bool, options map[string]interface{}) string {
+	t.Helper()
+
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: testIntrospectionSchema})
+	output := strings.Builder{}
+
+	decoded, err := (introspectionPlugin{}).DecodeOptions(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task := PluginTask{Schema: schema, Options: decoded, Sort: sortAlphabetically}
+	if err := (introspectionPlugin{}).Generate(task, &output); err != nil {
+		t.Fatal(err)
+	}
+
+	return output.String()
+}
+
+func introspectedNames(t *testing.T, output string) ([]string, []string) {
+	t.Helper()
+
+	var result struct {
+		Schema struct {
+			Types      []struct{ Name string }
+			Directives []struct{ Name string }
+		} `json:"__schema"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	var types, directives []string
+	for _, definition := range result.Schema.Types {
+		types = append(types, definition.Name)
+	}
+	for _, directive := range result.Schema.Directives {
+		directives = append(directives, directive.Name)
+	}
+
+	return types, directives
+}
+
+// TestIntrospectOrdering tests that types and directives are ordered like graphql-js orders them
+func TestIntrospectOrdering(t *testing.T) {
+	introspectionTypes := []string{"__Schema", "__Type", "__TypeKind", "__Field", "__InputValue", "__EnumValue", "__Directive", "__DirectiveLocation"}
+
+	types, directives := introspectedNames(t, introspect(t, false, nil))
+	expectedTypes := slices.Concat([]string{"Query", "ID", "Int", "Float", "User", "String", "Role", "Filter", "Boolean"}, introspectionTypes)
+	if !slices.Equal(types, expectedTypes) {
+		t.Errorf("unsorted types = %v, expected %v", types, expectedTypes)
+	}
+	if expected := []string{"auth", "include", "skip", "deprecated", "specifiedBy", "oneOf"}; !slices.Equal(directives, expected) {
+		t.Errorf("unsorted directives = %v, expected %v", directives, expected)
+	}
+
+	types, directives = introspectedNames(t, introspect(t, true, nil))
+	expectedTypes = slices.Concat([]string{"Boolean", "Filter", "Float", "ID", "Int", "Query", "Role", "String", "User"}, []string{
+		"__Directive", "__DirectiveLocation", "__EnumValue", "__Field", "__InputValue", "__Schema", "__Type", "__TypeKind",
+	})
+	if !slices.Equal(types, expectedTypes) {
+		t.Errorf("sorted types = %v, expected %v", types, expectedTypes)
+	}
+	if expected := []string{"auth", "deprecated", "include", "oneOf", "skip", "specifiedBy"}; !slices.Equal(directives, expected) {
+		t.Errorf("sorted directives = %v, expected %v", directives, expected)
+	}
+}
+
+// TestIntrospectFormat tests the JSON formatting and the values graphql-js would return
+func TestIntrospectFormat(t *testing.T) {
+	output := introspect(t, true, nil)
+	minified := introspect(t, true, map[string]interface{}{"minify": true})
+
+	// JSON.stringify with an indent formats like json.Indent
+	indented := bytes.Buffer{}
+	if err := json.Indent(&indented, []byte(minified), "", "  "); err != nil {
+		t.Fatal(err)
+	}
+	if indented.String() != output {
+		t.Errorf("indented output does not match minified output")
+	}
+
+	expectedFragments := []string{
+		`{"name":"filter","description":null,"type":{"kind":"INPUT_OBJECT","name":"Filter","ofType":null},"defaultValue":"{age: 3, name: \"<x>\"}","isDeprecated":false,"deprecationReason":null}`,
+		`{"name":"ratio","description":null,"type":{"kind":"SCALAR","name":"Float","ofType":null},"defaultValue":"1","isDeprecated":false,"deprecationReason":null}`,
+		`"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"LIST","name":null,"ofType":{"kind":"NON_NULL","name":null,"ofType":{"kind":"OBJECT","name":"User","ofType":null}}}},"isDeprecated":true,"deprecationReason":"Use user"}`,
+		`{"name":"GUEST","description":null,"isDeprecated":true,"deprecationReason":"No longer supported"}`,
+		`{"kind":"OBJECT","name":"User","description":"A user","isOneOf":null,"fields":[`,
+		`{"name":"auth","description":null,"isRepeatable":true,"locations":["FIELD_DEFINITION"],"args":[{"name":"role","description":null,"type":{"kind":"ENUM","name":"Role","ofType":null},"defaultValue":"ADMIN",`,
+		`"description":"The ` + "`Boolean`" + ` scalar type represents ` + "`true` or `false`" + `."`,
+	}
+	for _, fragment := range expectedFragments {
+		if !strings.Contains(minified, fragment) {
+			t.Errorf("output does not contain %s", fragment)
+		}
+	}
+
+	if strings.Contains(minified, `"__schema":{"description"`) || strings.Contains(minified, `"specifiedByURL":`) {
+		t.Errorf("output contains options that are off by default")
+	}
+}
+
+// TestIntrospectOptions tests that descriptions and repeatable directives can be left out
+func TestIntrospectOptions(t *testing.T) {
+	output := introspect(t, true, map[string]interface{}{
+		"minify":                true,
+		"descriptions":          false,
+		"directiveIsRepeatable": false,
+		"schemaDescription":     true,
+	})
+
+	if strings.Contains(output, `"description":"`) || strings.Contains(output, `"isRepeatable":`) {
+		t.Errorf("output contains options that were turned off")
+	}
+	if !strings.HasPrefix(output, `{"__schema":{"description":null,"queryType":{"name":"Query"}`) {
+		t.Errorf("output does not start with the schema description")
+	}
+
+	if _, err := (introspectionPlugin{}).DecodeOptions(map[string]interface{}{"minify": "yes"}); err == nil {
+		t.Errorf("invalid option was accepted")
+	}
+}
+
+// TestNaturalCompare tests that numbers in names are compared by value
+func TestNaturalCompare(t *testing.T) {
+	names := []string{"field10", "field2", "Field", "field", "_field", "field02"}
+	slices.SortFunc(names, NaturalCompare)
+
+	expected := []string{"Field", "_field", "field", "field02", "field2", "field10"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("sorted names = %v, expected %v", names, expected)
+	}
+}
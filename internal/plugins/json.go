@@ -0,0 +1,125 @@
+package plugins
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+jsonObject is a JSON object that keeps the order of its keys, like the objects graphql-js builds
+*/
+type jsonObject []jsonField
+
+type jsonField struct {
+	Key   string
+	Value interface{}
+}
+
+/*
+writeJSON writes value like JSON.stringify(value, null, indent) does, or like JSON.stringify(value) if indent is
+empty. Values are nil, bool, string, jsonObject or []interface{}.
+*/
+func writeJSON(output *strings.Builder, value interface{}, indent string) {
+	writeJSONValue(output, value, indent, "")
+}
+
+func writeJSONValue(output *strings.Builder, value interface{}, indent string, currentIndent string) {
+	nestedIndent := currentIndent + indent
+	separator := ":"
+	if indent != "" {
+		separator = ": "
+	}
+
+	switch v := value.(type) {
+	case nil:
+		output.WriteString("null")
+	case bool:
+		output.WriteString(strconv.FormatBool(v))
+	case string:
+		writeJSONString(output, v)
+	case jsonObject:
+		if len(v) == 0 {
+			output.WriteString("{}")
+			return
+		}
+
+		output.WriteString("{")
+		for i, field := range v {
+			if i > 0 {
+				output.WriteString(",")
+			}
+			writeJSONNewline(output, indent, nestedIndent)
+			writeJSONString(output, field.Key)
+			output.WriteString(separator)
+			writeJSONValue(output, field.Value, indent, nestedIndent)
+		}
+		writeJSONNewline(output, indent, currentIndent)
+		output.WriteString("}")
+	case []interface{}:
+		if len(v) == 0 {
+			output.WriteString("[]")
+			return
+		}
+
+		output.WriteString("[")
+		for i, item := range v {
+			if i > 0 {
+				output.WriteString(",")
+			}
+			writeJSONNewline(output, indent, nestedIndent)
+			writeJSONValue(output, item, indent, nestedIndent)
+		}
+		writeJSONNewline(output, indent, currentIndent)
+		output.WriteString("]")
+	default:
+		panic(fmt.Sprintf("unsupported JSON value %T", value))
+	}
+}
+
+func writeJSONNewline(output *strings.Builder, indent string, currentIndent string) {
+	if indent == "" {
+		return
+	}
+
+	output.WriteString("\n")
+	output.WriteString(currentIndent)
+}
+
+/*
+writeJSONString quotes a string like JSON.stringify, which leaves HTML characters and non-ASCII text unescaped
+*/
+func writeJSONString(output *strings.Builder, value string) {
+	output.WriteByte('"')
+
+	for i := 0; i < len(value); {
+		character, size := utf8.DecodeRuneInString(value[i:])
+		i += size
+
+		switch character {
+		case '"':
+			output.WriteString(`\"`)
+		case '\\':
+			output.WriteString(`\\`)
+		case '\b':
+			output.WriteString(`\b`)
+		case '\f':
+			output.WriteString(`\f`)
+		case '\n':
+			output.WriteString(`\n`)
+		case '\r':
+			output.WriteString(`\r`)
+		case '\t':
+			output.WriteString(`\t`)
+		default:
+			if character < 0x20 {
+				output.WriteString(fmt.Sprintf(`\u%04x`, character))
+			} else {
+				output.WriteRune(character)
+			}
+		}
+	}
+
+	output.WriteByte('"')
+}
@@ -0,0 +1,174 @@
+package plugins
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"slices"
+	"strings"
+)
+
+/*
+OrderedDefinitions returns the types of a schema in a stable order. When sortAlphabetically is set, types and
+their fields, arguments, enum values, interfaces and union members are sorted by NaturalCompare of their names,
+like upstream's sorted schema. Otherwise types are returned in the order they are defined in the schema sources,
+which are taken in sourceOrder.
+*/
+func OrderedDefinitions(schema *ast.Schema, sortAlphabetically bool, sourceOrder []string) []*ast.Definition {
+	definitions := make([]*ast.Definition, 0, len(schema.Types))
+	for _, definition := range schema.Types {
+		definitions = append(definitions, definition)
+	}
+
+	if sortAlphabetically {
+		slices.SortFunc(definitions, func(a, b *ast.Definition) int {
+			return NaturalCompare(a.Name, b.Name)
+		})
+
+		for i, definition := range definitions {
+			definitions[i] = sortDefinition(definition)
+		}
+
+		return definitions
+	}
+
+	slices.SortFunc(definitions, func(a, b *ast.Definition) int {
+		return compareDefinitionPositions(a, b, sourceOrder)
+	})
+
+	return definitions
+}
+
+/*
+compareDefinitionPositions orders definitions by source and offset, definitions without a position go last
+*/
+func compareDefinitionPositions(a *ast.Definition, b *ast.Definition, sourceOrder []string) int {
+	aPosition, bPosition := a.Position, b.Position
+
+	switch {
+	case aPosition == nil && bPosition == nil:
+		return strings.Compare(a.Name, b.Name)
+	case aPosition == nil:
+		return 1
+	case bPosition == nil:
+		return -1
+	}
+
+	// built-in definitions come first, like the prelude they are loaded from
+	if aPosition.Src.BuiltIn != bPosition.Src.BuiltIn {
+		if aPosition.Src.BuiltIn {
+			return -1
+		}
+		return 1
+	}
+
+	if order := compareSources(aPosition.Src, bPosition.Src, sourceOrder); order != 0 {
+		return order
+	}
+
+	if aPosition.Start != bPosition.Start {
+		return aPosition.Start - bPosition.Start
+	}
+
+	return strings.Compare(a.Name, b.Name)
+}
+
+/*
+compareSources orders sources by their index in sourceOrder, sources missing from it go last, by name
+*/
+func compareSources(a *ast.Source, b *ast.Source, sourceOrder []string) int {
+	aIndex, bIndex := slices.Index(sourceOrder, a.Name), slices.Index(sourceOrder, b.Name)
+
+	switch {
+	case aIndex == bIndex:
+		return strings.Compare(a.Name, b.Name)
+	case aIndex == -1:
+		return 1
+	case bIndex == -1:
+		return -1
+	}
+
+	return aIndex - bIndex
+}
+
+/*
+sortDefinition returns a copy of definition with all of its members sorted by name
+*/
+func sortDefinition(definition *ast.Definition) *ast.Definition {
+	sorted := *definition
+
+	sorted.Interfaces = slices.SortedStableFunc(slices.Values(definition.Interfaces), NaturalCompare)
+	sorted.Types = slices.SortedStableFunc(slices.Values(definition.Types), NaturalCompare)
+
+	sorted.Fields = slices.Clone(definition.Fields)
+	slices.SortStableFunc(sorted.Fields, func(a, b *ast.FieldDefinition) int {
+		return NaturalCompare(a.Name, b.Name)
+	})
+
+	for i, field := range sorted.Fields {
+		if len(field.Arguments) < 2 {
+			continue
+		}
+
+		sortedField := *field
+		sortedField.Arguments = slices.Clone(field.Arguments)
+		slices.SortStableFunc(sortedField.Arguments, func(a, b *ast.ArgumentDefinition) int {
+			return NaturalCompare(a.Name, b.Name)
+		})
+		sorted.Fields[i] = &sortedField
+	}
+
+	sorted.EnumValues = slices.Clone(definition.EnumValues)
+	slices.SortStableFunc(sorted.EnumValues, func(a, b *ast.EnumValueDefinition) int {
+		return NaturalCompare(a.Name, b.Name)
+	})
+
+	return &sorted
+}
+
+/*
+NaturalCompare compares names like graphql-js sorts schemas: by character code, except that runs of digits are
+compared by their numeric value
+*/
+func NaturalCompare(a string, b string) int {
+	aIndex, bIndex := 0, 0
+
+	for aIndex < len(a) && bIndex < len(b) {
+		aChar, bChar := a[aIndex], b[bIndex]
+
+		if !isDigit(aChar) || !isDigit(bChar) {
+			if aChar != bChar {
+				return int(aChar) - int(bChar)
+			}
+
+			aIndex++
+			bIndex++
+			continue
+		}
+
+		// a leading zero ends a number, like in graphql-js
+		aNumber, bNumber := 0, 0
+		for {
+			aNumber = aNumber*10 + int(a[aIndex]-'0')
+			aIndex++
+			if aIndex >= len(a) || !isDigit(a[aIndex]) || aNumber == 0 {
+				break
+			}
+		}
+		for {
+			bNumber = bNumber*10 + int(b[bIndex]-'0')
+			bIndex++
+			if bIndex >= len(b) || !isDigit(b[bIndex]) || bNumber == 0 {
+				break
+			}
+		}
+
+		if aNumber != bNumber {
+			return aNumber - bNumber
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+func isDigit(character byte) bool {
+	return character >= '0' && character <= '9'
+}
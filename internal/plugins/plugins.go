@@ -1,23 +1,163 @@
 package plugins
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/vektah/gqlparser/v2/ast"
+	"io"
+	"path/filepath"
+	"slices"
 	"strings"
 )
 
+/*
+Plugin generates one kind of output from a schema. Plugins add themselves to the registry with Register in an init
+function of their own file.
+*/
+type Plugin interface {
+	// Name is the name the plugin is listed with in config files
+	Name() string
+	// DecodeOptions decodes and checks the merged config of the plugin before anything is generated, the result is
+	// handed back to Generate as the Options of the task
+	DecodeOptions(config map[string]interface{}) (interface{}, error)
+	// Generate writes the output of the plugin for a task
+	Generate(task PluginTask, output io.Writer) error
+	// OutputExtensions lists the file extensions the plugin can write, any file can be written if it is empty
+	OutputExtensions() []string
+}
+
 type PluginTask struct {
 	Schema *ast.Schema
-	Output *strings.Builder
-	Config interface{}
+	// Options holds the options struct returned by DecodeOptions of the plugin
+	Options interface{}
+	// Documents holds the operations and fragments of the project, validated against Schema
+	Documents []*Document
+	// Sort outputs types sorted by name instead of in definition order
+	Sort bool
+	// SchemaFiles holds the schema files and URLs in the order of the schema pointers of the config, definition
+	// order follows it
+	SchemaFiles []string
+}
+
+/*
+Document is a GraphQL document of operations and fragments, loaded from the documents of a config
+*/
+type Document struct {
+	// File is the path of the file the document was read from
+	File string
+	// Source holds the text the document was parsed from, code around a document extracted from a source file is
+	// blanked out so positions match the file
+	Source *ast.Source
+	// Raw is the document as written, the content of the template literal for an extracted document
+	Raw      string
+	Document *ast.QueryDocument
+}
+
+var registry = make(map[string]Plugin)
+
+/*
+presetRegistry holds the plugins presets write their files with, config files cannot list them
+*/
+var presetRegistry = make(map[string]Plugin)
+
+/*
+Register adds a plugin to the registry, registering two plugins with the same name is a programming error
+*/
+func Register(plugin Plugin) {
+	if _, ok := registry[plugin.Name()]; ok {
+		panic("plugin " + plugin.Name() + " is registered twice")
+	}
+
+	registry[plugin.Name()] = plugin
+}
+
+/*
+Lookup finds a registered plugin by name, the error for an unknown plugin lists the available plugins
+*/
+func Lookup(name string) (Plugin, error) {
+	plugin, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown plugin %q, available plugins are %s", name, strings.Join(Names(), ", "))
+	}
+
+	return plugin, nil
+}
+
+/*
+registerPresetPlugin adds a plugin only presets can use, it is left out of Lookup and Names
+*/
+func registerPresetPlugin(plugin Plugin) {
+	if _, ok := presetRegistry[plugin.Name()]; ok {
+		panic("preset plugin " + plugin.Name() + " is registered twice")
+	}
+
+	presetRegistry[plugin.Name()] = plugin
+}
+
+/*
+LookupPresetPlugin finds a plugin by name for a file written by a preset, which may also use the plugins only
+presets can use
+*/
+func LookupPresetPlugin(name string) (Plugin, error) {
+	if plugin, ok := presetRegistry[name]; ok {
+		return plugin, nil
+	}
+
+	return Lookup(name)
 }
 
 /*
-VerifyPlugin checks if a plugin is executable by faster-graphql-codegen
+Names returns the names of all registered plugins, sorted
 */
-func VerifyPlugin(pluginName string, config interface{}) error {
-	if pluginName != "typescript" && pluginName != "introspection" {
-		return errors.New("unknown plugin")
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+/*
+ValidateDestination checks if a plugin can write to destination, based on its file extension
+*/
+func ValidateDestination(plugin Plugin, destination string) error {
+	extensions := plugin.OutputExtensions()
+	if len(extensions) == 0 {
+		return nil
+	}
+
+	for _, extension := range extensions {
+		if strings.HasSuffix(strings.ToLower(destination), extension) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot write %s, the output must be a %s file", filepath.Base(destination), strings.Join(extensions, " or "))
+}
+
+/*
+decodeOptions decodes plugin options into the options struct of a plugin, options unknown to the plugin are ignored
+since upstream shares them between plugins
+*/
+func decodeOptions(options map[string]interface{}, target interface{}) error {
+	if len(options) == 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(encoded, target); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("option %s must be a %s, got a %s", typeErr.Field, typeErr.Type.Kind(), typeErr.Value)
+		}
+		return err
 	}
 
 	return nil
@@ -0,0 +1,65 @@
+package plugins
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLookup tests that plugins are found by name and unknown plugins list the available ones
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"typescript", "typescript-operations", "typed-document-node", "introspection", "schema-ast"} {
+		if plugin, err := Lookup(name); err != nil || plugin.Name() != name {
+			t.Errorf("Lookup(%q) = %v, %v, expected the %s plugin", name, plugin, err, name)
+		}
+	}
+
+	_, err := Lookup("typescript-unknown")
+	if err == nil {
+		t.Fatal("Lookup() found an unknown plugin")
+	}
+	if expected := `unknown plugin "typescript-unknown", available plugins are introspection, schema-ast, typed-document-node, typescript, typescript-operations`; err.Error() != expected {
+		t.Errorf("Lookup() error = %q, expected %q", err, expected)
+	}
+}
+
+// TestLookupPresetPlugin tests that the plugins of presets are only found by presets
+func TestLookupPresetPlugin(t *testing.T) {
+	for _, name := range []string{"add", "gql-tag-operations", "typescript"} {
+		if plugin, err := LookupPresetPlugin(name); err != nil || plugin.Name() != name {
+			t.Errorf("LookupPresetPlugin(%q) = %v, %v, expected the %s plugin", name, plugin, err, name)
+		}
+	}
+
+	for _, name := range []string{"add", "gql-tag-operations"} {
+		if _, err := Lookup(name); err == nil {
+			t.Errorf("Lookup(%q) found a plugin of presets", name)
+		}
+	}
+}
+
+// TestValidateDestination tests that plugins only write files with the extensions they declare
+func TestValidateDestination(t *testing.T) {
+	tests := []struct {
+		plugin      string
+		destination string
+		wantErr     bool
+	}{
+		{"introspection", "schema.json", false},
+		{"introspection", "schema.ts", true},
+		{"schema-ast", "generated/schema.GRAPHQL", false},
+		{"schema-ast", "schema.json", true},
+		{"typescript", "types.anything", false},
+	}
+
+	for _, tt := range tests {
+		plugin, _ := Lookup(tt.plugin)
+
+		err := ValidateDestination(plugin, tt.destination)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateDestination(%s, %s) error = %v, wantErr %v", tt.plugin, tt.destination, err, tt.wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), plugin.OutputExtensions()[0]) {
+			t.Errorf("ValidateDestination() error = %q, expected it to name the extension", err)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+package plugins
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"io"
+	"slices"
+	"strings"
+)
+
+/*
+SchemaAstOptions are the options of the schema-ast plugin
+*/
+type SchemaAstOptions struct {
+	// IncludeDirectives prints the directives used on types, fields, arguments and enum values
+	IncludeDirectives bool `json:"includeDirectives"`
+	// Sort sorts types by name, it overrides the sort setting of the config
+	Sort *bool `json:"sort"`
+	// Descriptions includes descriptions, on by default
+	Descriptions *bool `json:"descriptions"`
+}
+
+func init() {
+	Register(schemaAstPlugin{})
+}
+
+/*
+schemaAstPlugin prints the schema back to SDL, formatted like printSchema of graphql-js
+*/
+type schemaAstPlugin struct{}
+
+func (schemaAstPlugin) Name() string {
+	return "schema-ast"
+}
+
+func (schemaAstPlugin) DecodeOptions(config map[string]interface{}) (interface{}, error) {
+	options := SchemaAstOptions{}
+	err := decodeOptions(config, &options)
+	return options, err
+}
+
+func (schemaAstPlugin) OutputExtensions() []string {
+	return []string{".graphql", ".gql", ".graphqls"}
+}
+
+func (schemaAstPlugin) Generate(task PluginTask, output io.Writer) error {
+	options, _ := task.Options.(SchemaAstOptions)
+
+	sortAlphabetically := task.Sort
+	if options.Sort != nil {
+		sortAlphabetically = *options.Sort
+	}
+
+	_, err := io.WriteString(output, PrintSchema(task.Schema, sortAlphabetically, task.SchemaFiles, options))
+	return err
+}
+
+/*
+PrintSchema prints the types and directives of a schema as SDL, built-in scalars, introspection types and
+directives are left out. Unless sortAlphabetically is set, they are in definition order, with the schema files in
+sourceOrder.
+*/
+func PrintSchema(schema *ast.Schema, sortAlphabetically bool, sourceOrder []string, options SchemaAstOptions) string {
+	i := newIntrospector(schema, sortAlphabetically, sourceOrder, IntrospectionOptions{})
+	printer := schemaPrinter{
+		introspector: i,
+		options:      options,
+	}
+
+	var definitions []string
+	if schemaDefinition := printer.schemaDefinition(schema); schemaDefinition != "" {
+		definitions = append(definitions, schemaDefinition)
+	}
+
+	// like graphql-js, directives named like a specified directive are never printed
+	specified := getSpecified()
+	for _, directive := range i.directives {
+		isSpecified := slices.ContainsFunc(specified.Directives, func(specifiedDirective *ast.DirectiveDefinition) bool {
+			return specifiedDirective.Name == directive.Name
+		})
+
+		if !isSpecified {
+			definitions = append(definitions, printer.directiveDefinition(directive))
+		}
+	}
+
+	for _, definition := range i.types {
+		if !definition.BuiltIn {
+			definitions = append(definitions, printer.definition(definition))
+		}
+	}
+
+	return strings.Join(definitions, "\n\n")
+}
+
+type schemaPrinter struct {
+	*introspector
+	options SchemaAstOptions
+}
+
+func (p *schemaPrinter) schemaDefinition(schema *ast.Schema) string {
+	isCommonName := func(definition *ast.Definition, name string) bool {
+		return definition == nil || definition.Name == name
+	}
+
+	if isCommonName(schema.Query, "Query") && isCommonName(schema.Mutation, "Mutation") && isCommonName(schema.Subscription, "Subscription") {
+		return ""
+	}
+
+	var operationTypes []string
+	if schema.Query != nil {
+		operationTypes = append(operationTypes, "  query: "+schema.Query.Name)
+	}
+	if schema.Mutation != nil {
+		operationTypes = append(operationTypes, "  mutation: "+schema.Mutation.Name)
+	}
+	if schema.Subscription != nil {
+		operationTypes = append(operationTypes, "  subscription: "+schema.Subscription.Name)
+	}
+
+	return p.description(schema.Description, "", true) + "schema {\n" + strings.Join(operationTypes, "\n") + "\n}"
+}
+
+func (p *schemaPrinter) directiveDefinition(directive *ast.DirectiveDefinition) string {
+	locations := make([]string, 0, len(directive.Locations))
+	for _, location := range directive.Locations {
+		locations = append(locations, string(location))
+	}
+
+	repeatable := ""
+	if directive.IsRepeatable {
+		repeatable = " repeatable"
+	}
+
+	return p.description(directive.Description, "", true) + "directive @" + directive.Name +
+		p.arguments(directive.Arguments, "") + repeatable + " on " + strings.Join(locations, " | ")
+}
+
+func (p *schemaPrinter) definition(definition *ast.Definition) string {
+	description := p.description(definition.Description, "", true)
+	directives := p.directives(definition.Directives)
+
+	switch definition.Kind {
+	case ast.Scalar:
+		if !p.options.IncludeDirectives {
+			if directive := definition.Directives.ForName("specifiedBy"); directive != nil {
+				directives = " @specifiedBy(url: " + printString(directive.Arguments.ForName("url").Value.Raw) + ")"
+			}
+		}
+		return description + "scalar " + definition.Name + directives
+	case ast.Object, ast.Interface:
+		keyword := "type "
+		if definition.Kind == ast.Interface {
+			keyword = "interface "
+		}
+
+		implements := ""
+		if len(definition.Interfaces) > 0 {
+			implements = " implements " + strings.Join(definition.Interfaces, " & ")
+		}
+
+		var fields []string
+		for _, field := range definition.Fields {
+			if strings.HasPrefix(field.Name, "__") {
+				continue
+			}
+
+			fields = append(fields, p.description(field.Description, "  ", len(fields) == 0)+"  "+field.Name+
+				p.arguments(field.Arguments, "  ")+": "+field.Type.String()+p.fieldDirectives(field.Directives))
+		}
+
+		return description + keyword + definition.Name + implements + directives + printBlock(fields)
+	case ast.Union:
+		types := ""
+		if len(definition.Types) > 0 {
+			types = " = " + strings.Join(definition.Types, " | ")
+		}
+		return description + "union " + definition.Name + directives + types
+	case ast.Enum:
+		var values []string
+		for index, value := range definition.EnumValues {
+			values = append(values, p.description(value.Description, "  ", index == 0)+"  "+value.Name+p.fieldDirectives(value.Directives))
+		}
+		return description + "enum " + definition.Name + directives + printBlock(values)
+	case ast.InputObject:
+		if !p.options.IncludeDirectives && definition.Directives.ForName("oneOf") != nil {
+			directives = " @oneOf"
+		}
+
+		var fields []string
+		for index, field := range definition.Fields {
+			fields = append(fields, p.description(field.Description, "  ", index == 0)+"  "+
+				p.inputValue(field.Name, field.Type, field.DefaultValue, field.Directives))
+		}
+		return description + "input " + definition.Name + directives + printBlock(fields)
+	}
+
+	return ""
+}
+
+func (p *schemaPrinter) arguments(arguments ast.ArgumentDefinitionList, indentation string) string {
+	if len(arguments) == 0 {
+		return ""
+	}
+
+	hasDescriptions := slices.ContainsFunc(arguments, func(argument *ast.ArgumentDefinition) bool {
+		return p.description(argument.Description, "", true) != ""
+	})
+
+	printed := make([]string, 0, len(arguments))
+	for index, argument := range arguments {
+		inputValue := p.inputValue(argument.Name, argument.Type, argument.DefaultValue, argument.Directives)
+		if hasDescriptions {
+			inputValue = p.description(argument.Description, "  "+indentation, index == 0) + "  " + indentation + inputValue
+		}
+		printed = append(printed, inputValue)
+	}
+
+	if !hasDescriptions {
+		return "(" + strings.Join(printed, ", ") + ")"
+	}
+
+	return "(\n" + strings.Join(printed, "\n") + "\n" + indentation + ")"
+}
+
+func (p *schemaPrinter) inputValue(name string, valueType *ast.Type, defaultValue *ast.Value, directives ast.DirectiveList) string {
+	printed := name + ": " + valueType.String()
+	if defaultValue != nil {
+		printed += " = " + p.printValue(defaultValue, valueType)
+	}
+
+	return printed + p.fieldDirectives(directives)
+}
+
+/*
+fieldDirectives prints the directives of fields, arguments and enum values, where @deprecated is always printed
+*/
+func (p *schemaPrinter) fieldDirectives(directives ast.DirectiveList) string {
+	if p.options.IncludeDirectives {
+		return p.directives(directives)
+	}
+
+	isDeprecated, reason := deprecation(directives)
+	if !isDeprecated {
+		return ""
+	}
+	if reason == "No longer supported" {
+		return " @deprecated"
+	}
+
+	return " @deprecated(reason: " + printString(reason.(string)) + ")"
+}
+
+func (p *schemaPrinter) directives(directives ast.DirectiveList) string {
+	if !p.options.IncludeDirectives {
+		return ""
+	}
+
+	printed := strings.Builder{}
+	for _, directive := range directives {
+		printed.WriteString(" @" + directive.Name)
+
+		if len(directive.Arguments) > 0 {
+			arguments := make([]string, 0, len(directive.Arguments))
+			for _, argument := range directive.Arguments {
+				arguments = append(arguments, argument.Name+": "+printUntypedValue(argument.Value))
+			}
+			printed.WriteString("(" + strings.Join(arguments, ", ") + ")")
+		}
+	}
+
+	return printed.String()
+}
+
+/*
+description prints a description as a block string on its own line, like printDescription of graphql-js
+*/
+func (p *schemaPrinter) description(description string, indentation string, firstInBlock bool) string {
+	if description == "" || (p.options.Descriptions != nil && !*p.options.Descriptions) {
+		return ""
+	}
+
+	printed := printString(description)
+	if isPrintableAsBlockString(description) {
+		printed = printBlockString(description)
+	}
+
+	prefix := indentation
+	if indentation != "" && !firstInBlock {
+		prefix = "\n" + indentation
+	}
+
+	return prefix + strings.ReplaceAll(printed, "\n", "\n"+indentation) + "\n"
+}
+
+func printBlock(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+
+	return " {\n" + strings.Join(items, "\n") + "\n}"
+}
+
+/*
+printBlockString prints a value as a block string like graphql-js does
+*/
+func printBlockString(value string) string {
+	escapedValue := strings.ReplaceAll(value, `"""`, `\"""`)
+	lines := strings.Split(strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(escapedValue), "\n")
+	isSingleLine := len(lines) == 1
+
+	// common indentation is kept by starting on a new line
+	forceLeadingNewLine := len(lines) > 1 && !slices.ContainsFunc(lines[1:], func(line string) bool {
+		return line != "" && !isWhiteSpace(line[0])
+	})
+
+	hasTrailingTripleQuotes := strings.HasSuffix(escapedValue, `\"""`)
+	hasTrailingQuote := strings.HasSuffix(value, `"`) && !hasTrailingTripleQuotes
+	hasTrailingSlash := strings.HasSuffix(value, `\`)
+	forceTrailingNewline := hasTrailingQuote || hasTrailingSlash
+
+	printAsMultipleLines := !isSingleLine || len(value) > 70 || forceTrailingNewline || forceLeadingNewLine ||
+		hasTrailingTripleQuotes
+
+	result := ""
+	skipLeadingNewLine := isSingleLine && value != "" && isWhiteSpace(value[0])
+	if (printAsMultipleLines && !skipLeadingNewLine) || forceLeadingNewLine {
+		result += "\n"
+	}
+	result += escapedValue
+	if printAsMultipleLines || forceTrailingNewline {
+		result += "\n"
+	}
+
+	return `"""` + result + `"""`
+}
+
+/*
+isPrintableAsBlockString checks if a block string would read back as the same value
+*/
+func isPrintableAsBlockString(value string) bool {
+	isEmptyLine := true
+	hasIndent := false
+	hasCommonIndent := true
+	seenNonEmptyLine := false
+
+	for _, character := range value {
+		switch {
+		case character == '\r' || (character < 0x20 && character != '\n' && character != '\t'):
+			return false
+		case character == '\n':
+			// a leading new line would be removed
+			if isEmptyLine && !seenNonEmptyLine {
+				return false
+			}
+			seenNonEmptyLine = true
+			isEmptyLine = true
+			hasIndent = false
+		case character == '\t' || character == ' ':
+			hasIndent = hasIndent || isEmptyLine
+		default:
+			hasCommonIndent = hasCommonIndent && hasIndent
+			isEmptyLine = false
+		}
+	}
+
+	// trailing empty lines and common indentation would be removed
+	if isEmptyLine {
+		return false
+	}
+
+	return !(hasCommonIndent && seenNonEmptyLine)
+}
+
+func isWhiteSpace(character byte) bool {
+	return character == ' ' || character == '\t'
+}
@@ -0,0 +1,132 @@
+package plugins
+
+import (
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"testing"
+)
+
+const testSchemaAstSchema = `
+schema { query: Root }
+
+"The root"
+type Root {
+	user(
+		"The id"
+		id: ID!
+		active: Boolean = true
+	): User @deprecated(reason: "Use node")
+	node(id: ID!): Node @cached
+}
+
+interface Node { id: ID! }
+
+type User implements Node @key(fields: "id") {
+	id: ID!
+	name: String
+}
+
+directive @cached on FIELD_DEFINITION
+directive @key(fields: String!) on OBJECT
+`
+
+// TestPrintSchema tests that schemas are printed like printSchema of graphql-js
+func TestPrintSchema(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: testSchemaAstSchema})
+	disabled := false
+
+	tests := []struct {
+		name               string
+		sortAlphabetically bool
+		options            SchemaAstOptions
+		expected           string
+	}{
+		{
+			name: "DefinitionOrder",
+			expected: `schema {
+  query: Root
+}
+
+directive @cached on FIELD_DEFINITION
+
+directive @key(fields: String!) on OBJECT
+
+"""The root"""
+type Root {
+  user(
+    """The id"""
+    id: ID!
+    active: Boolean = true
+  ): User @deprecated(reason: "Use node")
+  node(id: ID!): Node
+}
+
+interface Node {
+  id: ID!
+}
+
+type User implements Node {
+  id: ID!
+  name: String
+}`,
+		},
+		{
+			name:               "SortedWithDirectivesWithoutDescriptions",
+			sortAlphabetically: true,
+			options:            SchemaAstOptions{IncludeDirectives: true, Descriptions: &disabled},
+			expected: `schema {
+  query: Root
+}
+
+directive @cached on FIELD_DEFINITION
+
+directive @key(fields: String!) on OBJECT
+
+interface Node {
+  id: ID!
+}
+
+type Root {
+  node(id: ID!): Node @cached
+  user(active: Boolean = true, id: ID!): User @deprecated(reason: "Use node")
+}
+
+type User implements Node @key(fields: "id") {
+  id: ID!
+  name: String
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if printed := PrintSchema(schema, tt.sortAlphabetically, nil, tt.options); printed != tt.expected {
+				t.Errorf("PrintSchema() =\n%s\nexpected\n%s", printed, tt.expected)
+			}
+		})
+	}
+}
+
+// TestPrintBlockString tests that descriptions are quoted like graphql-js quotes them
+func TestPrintBlockString(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"Short", `"""Short"""`},
+		{"Two\nlines", "\"\"\"\nTwo\nlines\n\"\"\""},
+		{`Ends with "quote"`, "\"\"\"\nEnds with \"quote\"\n\"\"\""},
+		{` Leading space`, `""" Leading space"""`},
+		{`Has """ quotes`, `"""Has \""" quotes"""`},
+	}
+
+	for _, tt := range tests {
+		if printed := printBlockString(tt.value); printed != tt.expected {
+			t.Errorf("printBlockString(%q) = %q, expected %q", tt.value, printed, tt.expected)
+		}
+	}
+
+	if isPrintableAsBlockString("\nLeading new line") || isPrintableAsBlockString("Trailing new line\n") {
+		t.Errorf("isPrintableAsBlockString() accepted a value a block string would change")
+	}
+}
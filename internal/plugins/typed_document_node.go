@@ -0,0 +1,503 @@
+package plugins
+
+import (
+	"fmt"
+	"github.com/vektah/gqlparser/v2/ast"
+	"io"
+	"slices"
+	"strings"
+)
+
+/*
+TypedDocumentNodeOptions are the options of the typed-document-node plugin
+*/
+type TypedDocumentNodeOptions struct {
+	// DocumentMode is documentNode to write documents as parsed ASTs, or string to write them as GraphQL strings
+	DocumentMode string `json:"documentMode"`
+}
+
+func init() {
+	Register(typedDocumentNodePlugin{})
+}
+
+/*
+typedDocumentNodePlugin writes the operations and fragments of the documents as documents typed with their result and
+variables types, like @graphql-codegen/typed-document-node. The types are those of the typescript-operations plugin.
+*/
+type typedDocumentNodePlugin struct{}
+
+func (typedDocumentNodePlugin) Name() string {
+	return "typed-document-node"
+}
+
+func (typedDocumentNodePlugin) DecodeOptions(config map[string]interface{}) (interface{}, error) {
+	options := TypedDocumentNodeOptions{}
+	if err := decodeOptions(config, &options); err != nil {
+		return options, err
+	}
+
+	if options.DocumentMode != "" && options.DocumentMode != "documentNode" && options.DocumentMode != "string" {
+		return options, fmt.Errorf("unknown documentMode %s, expected documentNode or string", options.DocumentMode)
+	}
+
+	return options, nil
+}
+
+func (typedDocumentNodePlugin) OutputExtensions() []string {
+	return nil
+}
+
+func (typedDocumentNodePlugin) Generate(task PluginTask, output io.Writer) error {
+	options, _ := task.Options.(TypedDocumentNodeOptions)
+
+	converted := strings.Builder{}
+	ConvertTypedDocuments(task.Documents, &converted, options)
+
+	_, err := io.WriteString(output, converted.String())
+	return err
+}
+
+/*
+typedDocumentString is the class string documents are written as, like upstream declares it
+*/
+const typedDocumentString = `import { DocumentTypeDecoration } from '@graphql-typed-document-node/core';
+
+export class TypedDocumentString<TResult, TVariables>
+	extends String
+	implements DocumentTypeDecoration<TResult, TVariables>
+{
+	__apiType?: DocumentTypeDecoration<TResult, TVariables>['__apiType'];
+	private value: string;
+	public __meta__?: Record<string, any> | undefined;
+
+	constructor(value: string, __meta__?: Record<string, any> | undefined) {
+		super(value);
+		this.value = value;
+		this.__meta__ = __meta__;
+	}
+
+	toString(): string & DocumentTypeDecoration<TResult, TVariables> {
+		return this.value;
+	}
+}
+`
+
+/*
+ConvertTypedDocuments writes a typed document for every named operation and every fragment of documents, e.g.
+GetUserDocument and UserFieldsFragmentDoc. Each document holds the fragments it spreads, directly or through other
+fragments, so it can be sent on its own. An operation or fragment written in several documents is written once.
+*/
+func ConvertTypedDocuments(documents []*Document, output *strings.Builder, options TypedDocumentNodeOptions) {
+	fragments := AllFragments(documents)
+
+	if options.DocumentMode == "string" {
+		output.WriteString(typedDocumentString + "\n")
+	} else {
+		output.WriteString("import { TypedDocumentNode as DocumentNode } from '@graphql-typed-document-node/core';\n\n")
+	}
+
+	written := make(map[string]bool)
+	for _, document := range documents {
+		for _, definition := range documentDefinitions(document.Document) {
+			// anonymous operations have nothing to name their document after
+			if operation, ok := definition.(*ast.OperationDefinition); ok && operation.Name == "" {
+				continue
+			}
+			if written[TypedDocumentName(definition)] {
+				continue
+			}
+			written[TypedDocumentName(definition)] = true
+
+			switch d := definition.(type) {
+			case *ast.OperationDefinition:
+
+				definitions := append([]interface{}{d}, fragmentDependencies(fragments, d.SelectionSet, nil)...)
+				writeTypedDocument(output, TypedDocumentName(d), definitions, "",
+					OperationTypeName(d), OperationTypeName(d)+"Variables", options)
+			case *ast.FragmentDefinition:
+				definitions := append([]interface{}{d}, fragmentDependencies(fragments, d.SelectionSet, []string{d.Name})...)
+				writeTypedDocument(output, TypedDocumentName(d), definitions, d.Name,
+					FragmentTypeName(d), "unknown", options)
+			}
+		}
+	}
+}
+
+/*
+TypedDocumentName names the typed document of an operation or fragment, e.g. GetUserDocument or UserFieldsFragmentDoc
+*/
+func TypedDocumentName(definition interface{}) string {
+	switch d := definition.(type) {
+	case *ast.OperationDefinition:
+		return ToCamel(d.Name) + "Document"
+	case *ast.FragmentDefinition:
+		return ToCamel(d.Name) + "FragmentDoc"
+	}
+
+	return ""
+}
+
+func writeTypedDocument(
+	output *strings.Builder,
+	name string,
+	definitions []interface{},
+	fragmentName string,
+	resultType string,
+	variablesType string,
+	options TypedDocumentNodeOptions,
+) {
+	output.WriteString("export const " + name + " = ")
+
+	if options.DocumentMode == "string" {
+		printed := make([]string, 0, len(definitions))
+		for _, definition := range definitions {
+			printed = append(printed, printQueryDefinition(definition))
+		}
+
+		output.WriteString("new TypedDocumentString(`\n" + escapeTemplate(strings.Join(printed, "\n")) + "\n`")
+		if fragmentName != "" {
+			output.WriteString(", ")
+			writeJSON(output, jsonObject{{"fragmentName", fragmentName}}, "")
+		}
+		output.WriteString(") as unknown as TypedDocumentString<" + resultType + ", " + variablesType + ">;\n")
+		return
+	}
+
+	nodes := make([]interface{}, 0, len(definitions))
+	for _, definition := range definitions {
+		nodes = append(nodes, definitionNode(definition))
+	}
+
+	writeJSON(output, jsonObject{{"kind", "Document"}, {"definitions", nodes}}, "")
+	output.WriteString(" as unknown as DocumentNode<" + resultType + ", " + variablesType + ">;\n")
+}
+
+/*
+fragmentDependencies returns the fragments spread in a selection set and in the fragments it spreads, in the order
+they are first spread. Fragments named in seen are left out.
+*/
+func fragmentDependencies(fragments ast.FragmentDefinitionList, selections ast.SelectionSet, seen []string) []interface{} {
+	var dependencies []interface{}
+
+	var collect func(selections ast.SelectionSet)
+	collect = func(selections ast.SelectionSet) {
+		for _, selection := range selections {
+			switch s := selection.(type) {
+			case *ast.Field:
+				collect(s.SelectionSet)
+			case *ast.InlineFragment:
+				collect(s.SelectionSet)
+			case *ast.FragmentSpread:
+				fragment := fragments.ForName(s.Name)
+				if fragment == nil || slices.Contains(seen, s.Name) {
+					continue
+				}
+
+				seen = append(seen, s.Name)
+				dependencies = append(dependencies, fragment)
+				collect(fragment.SelectionSet)
+			}
+		}
+	}
+	collect(selections)
+
+	return dependencies
+}
+
+/*
+escapeTemplate escapes a string to be written in a template literal
+*/
+func escapeTemplate(value string) string {
+	return strings.NewReplacer("\\", "\\\\", "`", "\\`", "${", "\\${").Replace(value)
+}
+
+/*
+definitionNode builds the graphql-js AST of an operation or fragment definition. Locations are left out, and so are
+empty lists like upstream optimizes them away.
+*/
+func definitionNode(definition interface{}) jsonObject {
+	switch d := definition.(type) {
+	case *ast.OperationDefinition:
+		node := jsonObject{{"kind", "OperationDefinition"}, {"operation", string(d.Operation)}}
+		if d.Name != "" {
+			node = append(node, jsonField{"name", nameNode(d.Name)})
+		}
+
+		variables := make([]interface{}, 0, len(d.VariableDefinitions))
+		for _, variable := range d.VariableDefinitions {
+			variableNode := jsonObject{
+				{"kind", "VariableDefinition"},
+				{"variable", jsonObject{{"kind", "Variable"}, {"name", nameNode(variable.Variable)}}},
+				{"type", typeNode(variable.Type)},
+			}
+			if variable.DefaultValue != nil {
+				variableNode = append(variableNode, jsonField{"defaultValue", valueNode(variable.DefaultValue)})
+			}
+			variables = append(variables, appendNodes(variableNode, "directives", directiveNodes(variable.Directives)))
+		}
+
+		node = appendNodes(node, "variableDefinitions", variables)
+		node = appendNodes(node, "directives", directiveNodes(d.Directives))
+		return append(node, jsonField{"selectionSet", selectionSetNode(d.SelectionSet)})
+	case *ast.FragmentDefinition:
+		node := jsonObject{
+			{"kind", "FragmentDefinition"},
+			{"name", nameNode(d.Name)},
+			{"typeCondition", namedTypeNode(d.TypeCondition)},
+		}
+		node = appendNodes(node, "directives", directiveNodes(d.Directives))
+		return append(node, jsonField{"selectionSet", selectionSetNode(d.SelectionSet)})
+	}
+
+	return nil
+}
+
+func selectionSetNode(selections ast.SelectionSet) jsonObject {
+	nodes := make([]interface{}, 0, len(selections))
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			node := jsonObject{{"kind", "Field"}}
+			if s.Alias != "" && s.Alias != s.Name {
+				node = append(node, jsonField{"alias", nameNode(s.Alias)})
+			}
+			node = append(node, jsonField{"name", nameNode(s.Name)})
+			node = appendNodes(node, "arguments", argumentNodes(s.Arguments))
+			node = appendNodes(node, "directives", directiveNodes(s.Directives))
+			if len(s.SelectionSet) > 0 {
+				node = append(node, jsonField{"selectionSet", selectionSetNode(s.SelectionSet)})
+			}
+			nodes = append(nodes, node)
+		case *ast.InlineFragment:
+			node := jsonObject{{"kind", "InlineFragment"}}
+			if s.TypeCondition != "" {
+				node = append(node, jsonField{"typeCondition", namedTypeNode(s.TypeCondition)})
+			}
+			node = appendNodes(node, "directives", directiveNodes(s.Directives))
+			nodes = append(nodes, append(node, jsonField{"selectionSet", selectionSetNode(s.SelectionSet)}))
+		case *ast.FragmentSpread:
+			node := jsonObject{{"kind", "FragmentSpread"}, {"name", nameNode(s.Name)}}
+			nodes = append(nodes, appendNodes(node, "directives", directiveNodes(s.Directives)))
+		}
+	}
+
+	return jsonObject{{"kind", "SelectionSet"}, {"selections", nodes}}
+}
+
+func argumentNodes(arguments ast.ArgumentList) []interface{} {
+	nodes := make([]interface{}, 0, len(arguments))
+	for _, argument := range arguments {
+		nodes = append(nodes, jsonObject{{"kind", "Argument"}, {"name", nameNode(argument.Name)}, {"value", valueNode(argument.Value)}})
+	}
+
+	return nodes
+}
+
+func directiveNodes(directives ast.DirectiveList) []interface{} {
+	nodes := make([]interface{}, 0, len(directives))
+	for _, directive := range directives {
+		node := jsonObject{{"kind", "Directive"}, {"name", nameNode(directive.Name)}}
+		nodes = append(nodes, appendNodes(node, "arguments", argumentNodes(directive.Arguments)))
+	}
+
+	return nodes
+}
+
+func valueNode(value *ast.Value) jsonObject {
+	switch value.Kind {
+	case ast.Variable:
+		return jsonObject{{"kind", "Variable"}, {"name", nameNode(value.Raw)}}
+	case ast.IntValue:
+		return jsonObject{{"kind", "IntValue"}, {"value", value.Raw}}
+	case ast.FloatValue:
+		return jsonObject{{"kind", "FloatValue"}, {"value", value.Raw}}
+	case ast.StringValue, ast.BlockValue:
+		return jsonObject{{"kind", "StringValue"}, {"value", value.Raw}, {"block", value.Kind == ast.BlockValue}}
+	case ast.BooleanValue:
+		return jsonObject{{"kind", "BooleanValue"}, {"value", value.Raw == "true"}}
+	case ast.NullValue:
+		return jsonObject{{"kind", "NullValue"}}
+	case ast.EnumValue:
+		return jsonObject{{"kind", "EnumValue"}, {"value", value.Raw}}
+	case ast.ListValue:
+		values := make([]interface{}, 0, len(value.Children))
+		for _, child := range value.Children {
+			values = append(values, valueNode(child.Value))
+		}
+		return appendNodes(jsonObject{{"kind", "ListValue"}}, "values", values)
+	case ast.ObjectValue:
+		fields := make([]interface{}, 0, len(value.Children))
+		for _, child := range value.Children {
+			fields = append(fields, jsonObject{{"kind", "ObjectField"}, {"name", nameNode(child.Name)}, {"value", valueNode(child.Value)}})
+		}
+		return appendNodes(jsonObject{{"kind", "ObjectValue"}}, "fields", fields)
+	}
+
+	return nil
+}
+
+func typeNode(valueType *ast.Type) jsonObject {
+	var node jsonObject
+	if valueType.Elem != nil {
+		node = jsonObject{{"kind", "ListType"}, {"type", typeNode(valueType.Elem)}}
+	} else {
+		node = namedTypeNode(valueType.NamedType)
+	}
+
+	if valueType.NonNull {
+		return jsonObject{{"kind", "NonNullType"}, {"type", node}}
+	}
+
+	return node
+}
+
+func namedTypeNode(name string) jsonObject {
+	return jsonObject{{"kind", "NamedType"}, {"name", nameNode(name)}}
+}
+
+func nameNode(name string) jsonObject {
+	return jsonObject{{"kind", "Name"}, {"value", name}}
+}
+
+/*
+appendNodes adds a list of nodes to a node, unless the list is empty
+*/
+func appendNodes(node jsonObject, key string, nodes []interface{}) jsonObject {
+	if len(nodes) == 0 {
+		return node
+	}
+
+	return append(node, jsonField{key, nodes})
+}
+
+/*
+printQueryDefinition prints an operation or fragment definition like the print of graphql-js
+*/
+func printQueryDefinition(definition interface{}) string {
+	switch d := definition.(type) {
+	case *ast.OperationDefinition:
+		variables := make([]string, 0, len(d.VariableDefinitions))
+		for _, variable := range d.VariableDefinitions {
+			printed := "$" + variable.Variable + ": " + variable.Type.String()
+			if variable.DefaultValue != nil {
+				printed += " = " + printQueryValue(variable.DefaultValue)
+			}
+			variables = append(variables, joinNonEmpty([]string{printed, printDirectives(variable.Directives)}, " "))
+		}
+
+		prefix := joinNonEmpty([]string{
+			string(d.Operation),
+			d.Name + wrapNonEmpty("(", strings.Join(variables, ", "), ")"),
+			printDirectives(d.Directives),
+		}, " ")
+		if prefix == "query" {
+			return printSelectionSet(d.SelectionSet)
+		}
+
+		return prefix + " " + printSelectionSet(d.SelectionSet)
+	case *ast.FragmentDefinition:
+		return "fragment " + d.Name + " on " + d.TypeCondition + " " +
+			wrapNonEmpty("", printDirectives(d.Directives), " ") + printSelectionSet(d.SelectionSet)
+	}
+
+	return ""
+}
+
+func printSelectionSet(selections ast.SelectionSet) string {
+	printed := make([]string, 0, len(selections))
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			prefix := wrapNonEmpty("", s.Alias, ": ") + s.Name
+			if s.Alias == s.Name {
+				prefix = s.Name
+			}
+
+			arguments := make([]string, 0, len(s.Arguments))
+			for _, argument := range s.Arguments {
+				arguments = append(arguments, argument.Name+": "+printQueryValue(argument.Value))
+			}
+
+			// like graphql-js, arguments go on their own lines if they do not fit on one
+			line := prefix + wrapNonEmpty("(", strings.Join(arguments, ", "), ")")
+			if len(line) > 80 {
+				line = prefix + wrapNonEmpty("(\n", indentBlock(strings.Join(arguments, "\n")), "\n)")
+			}
+
+			printed = append(printed, joinNonEmpty([]string{line, printDirectives(s.Directives), printSelectionSet(s.SelectionSet)}, " "))
+		case *ast.InlineFragment:
+			printed = append(printed, joinNonEmpty([]string{
+				"...",
+				wrapNonEmpty("on ", s.TypeCondition, ""),
+				printDirectives(s.Directives),
+				printSelectionSet(s.SelectionSet),
+			}, " "))
+		case *ast.FragmentSpread:
+			printed = append(printed, "..."+s.Name+wrapNonEmpty(" ", printDirectives(s.Directives), ""))
+		}
+	}
+
+	if len(printed) == 0 {
+		return ""
+	}
+
+	return "{\n" + indentBlock(strings.Join(printed, "\n")) + "\n}"
+}
+
+func printDirectives(directives ast.DirectiveList) string {
+	printed := make([]string, 0, len(directives))
+	for _, directive := range directives {
+		arguments := make([]string, 0, len(directive.Arguments))
+		for _, argument := range directive.Arguments {
+			arguments = append(arguments, argument.Name+": "+printQueryValue(argument.Value))
+		}
+
+		printed = append(printed, "@"+directive.Name+wrapNonEmpty("(", strings.Join(arguments, ", "), ")"))
+	}
+
+	return strings.Join(printed, " ")
+}
+
+/*
+printQueryValue prints a value of a document, which unlike values of a schema may be a variable
+*/
+func printQueryValue(value *ast.Value) string {
+	switch value.Kind {
+	case ast.Variable:
+		return "$" + value.Raw
+	case ast.StringValue:
+		return printString(value.Raw)
+	case ast.BlockValue:
+		return printBlockString(value.Raw)
+	case ast.ListValue:
+		items := make([]string, 0, len(value.Children))
+		for _, child := range value.Children {
+			items = append(items, printQueryValue(child.Value))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case ast.ObjectValue:
+		fields := make([]string, 0, len(value.Children))
+		for _, child := range value.Children {
+			fields = append(fields, child.Name+": "+printQueryValue(child.Value))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return value.Raw
+	}
+}
+
+func joinNonEmpty(items []string, separator string) string {
+	return strings.Join(slices.DeleteFunc(slices.Clone(items), func(item string) bool { return item == "" }), separator)
+}
+
+func wrapNonEmpty(start string, value string, end string) string {
+	if value == "" {
+		return ""
+	}
+
+	return start + value + end
+}
+
+func indentBlock(value string) string {
+	return "  " + strings.ReplaceAll(value, "\n", "\n  ")
+}
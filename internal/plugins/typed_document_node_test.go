@@ -0,0 +1,90 @@
+package plugins
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestConvertTypedDocuments tests the documents written for operations and fragments, and the fragments they include
+func TestConvertTypedDocuments(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		options  TypedDocumentNodeOptions
+		expected string
+	}{
+		{
+			name:     "Query",
+			document: `query GetUser($id: ID!) { me: user(id: $id) { id } }`,
+			expected: `export const GetUserDocument = {"kind":"Document","definitions":[{"kind":"OperationDefinition",` +
+				`"operation":"query","name":{"kind":"Name","value":"GetUser"},"variableDefinitions":[{"kind":"VariableDefinition",` +
+				`"variable":{"kind":"Variable","name":{"kind":"Name","value":"id"}},"type":{"kind":"NonNullType","type":` +
+				`{"kind":"NamedType","name":{"kind":"Name","value":"ID"}}}}],"selectionSet":{"kind":"SelectionSet","selections":` +
+				`[{"kind":"Field","alias":{"kind":"Name","value":"me"},"name":{"kind":"Name","value":"user"},"arguments":` +
+				`[{"kind":"Argument","name":{"kind":"Name","value":"id"},"value":{"kind":"Variable","name":{"kind":"Name","value":"id"}}}],` +
+				`"selectionSet":{"kind":"SelectionSet","selections":[{"kind":"Field","name":{"kind":"Name","value":"id"}}]}}]}}]}` +
+				" as unknown as DocumentNode<GetUserQuery, GetUserQueryVariables>;\n",
+		},
+		{
+			name:     "TransitiveFragments",
+			document: `query Node { node(id: 1) { ...NodeFields } } fragment NodeFields on Node { ... on Post { ...PostFields } } fragment PostFields on Post { title }`,
+			expected: `{"kind":"Document","definitions":[{"kind":"OperationDefinition","operation":"query","name":{"kind":"Name","value":"Node"},` +
+				`"selectionSet":{"kind":"SelectionSet","selections":[{"kind":"Field","name":{"kind":"Name","value":"node"},"arguments":` +
+				`[{"kind":"Argument","name":{"kind":"Name","value":"id"},"value":{"kind":"IntValue","value":"1"}}],"selectionSet":` +
+				`{"kind":"SelectionSet","selections":[{"kind":"FragmentSpread","name":{"kind":"Name","value":"NodeFields"}}]}}]}},` +
+				`{"kind":"FragmentDefinition","name":{"kind":"Name","value":"NodeFields"},"typeCondition":{"kind":"NamedType","name":` +
+				`{"kind":"Name","value":"Node"}},"selectionSet":{"kind":"SelectionSet","selections":[{"kind":"InlineFragment",` +
+				`"typeCondition":{"kind":"NamedType","name":{"kind":"Name","value":"Post"}},"selectionSet":{"kind":"SelectionSet",` +
+				`"selections":[{"kind":"FragmentSpread","name":{"kind":"Name","value":"PostFields"}}]}}]}},{"kind":"FragmentDefinition",` +
+				`"name":{"kind":"Name","value":"PostFields"},"typeCondition":{"kind":"NamedType","name":{"kind":"Name","value":"Post"}},` +
+				`"selectionSet":{"kind":"SelectionSet","selections":[{"kind":"Field","name":{"kind":"Name","value":"title"}}]}}]}` +
+				" as unknown as DocumentNode<NodeQuery, NodeQueryVariables>;\n",
+		},
+		{
+			name:     "Fragment",
+			document: `fragment PostFields on Post { title @include(if: true) }`,
+			expected: `export const PostFieldsFragmentDoc = {"kind":"Document","definitions":[{"kind":"FragmentDefinition",` +
+				`"name":{"kind":"Name","value":"PostFields"},"typeCondition":{"kind":"NamedType","name":{"kind":"Name","value":"Post"}},` +
+				`"selectionSet":{"kind":"SelectionSet","selections":[{"kind":"Field","name":{"kind":"Name","value":"title"},` +
+				`"directives":[{"kind":"Directive","name":{"kind":"Name","value":"include"},"arguments":[{"kind":"Argument",` +
+				`"name":{"kind":"Name","value":"if"},"value":{"kind":"BooleanValue","value":true}}]}]}]}}]}` +
+				" as unknown as DocumentNode<PostFieldsFragment, unknown>;\n",
+		},
+		{
+			name:     "StringDocuments",
+			document: `query Search($term: String! = "a") { search(term: $term) { ...PostFields } } fragment PostFields on Post { title }`,
+			options:  TypedDocumentNodeOptions{DocumentMode: "string"},
+			expected: "export const SearchDocument = new TypedDocumentString(`\n" +
+				"query Search($term: String! = \"a\") {\n  search(term: $term) {\n    ...PostFields\n  }\n}\n" +
+				"fragment PostFields on Post {\n  title\n}\n" +
+				"`) as unknown as TypedDocumentString<SearchQuery, SearchQueryVariables>;\n" +
+				"export const PostFieldsFragmentDoc = new TypedDocumentString(`\nfragment PostFields on Post {\n  title\n}\n`, " +
+				`{"fragmentName":"PostFields"}) as unknown as TypedDocumentString<PostFieldsFragment, unknown>;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := strings.Builder{}
+			ConvertTypedDocuments(testDocuments(t, tt.document), &output, tt.options)
+
+			if !strings.Contains(output.String(), tt.expected) {
+				t.Errorf("ConvertTypedDocuments() = %s, expected it to contain %s", output.String(), tt.expected)
+			}
+		})
+	}
+}
+
+// TestConvertTypedDocumentsDuplicates tests that a document written in several files is written once
+func TestConvertTypedDocumentsDuplicates(t *testing.T) {
+	document := `query GetUser { user(id: 1) { ...UserFields } } fragment UserFields on User { id }`
+
+	output := strings.Builder{}
+	ConvertTypedDocuments(testDocuments(t, document, document), &output, TypedDocumentNodeOptions{})
+
+	for _, name := range []string{"export const GetUserDocument ", "export const UserFieldsFragmentDoc "} {
+		if count := strings.Count(output.String(), name); count != 1 {
+			t.Errorf("ConvertTypedDocuments() wrote %q %d times, expected once", name, count)
+		}
+	}
+}
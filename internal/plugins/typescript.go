@@ -1,32 +1,161 @@
 package plugins
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/vektah/gqlparser/v2/ast"
+	"io"
 	"log/slog"
+	"slices"
 	"strings"
-	"time"
 )
 
-func (p *PluginTask) Typescript() {
-	ConvertSchema(p.Schema, p.Output)
+/*
+TypescriptOptions are the options of the typescript plugin
+*/
+type TypescriptOptions struct {
+	// Scalars maps scalars to the Typescript types they are sent and received as
+	Scalars map[string]ScalarType `json:"scalars"`
+	// DefaultScalarType is the type of custom scalars missing from Scalars, any by default
+	DefaultScalarType string `json:"defaultScalarType"`
+	// EnumsAsTypes writes enums as unions of string literals
+	EnumsAsTypes bool `json:"enumsAsTypes"`
+	// EnumsAsConst writes enums as objects declared as const, with a type of their values
+	EnumsAsConst bool `json:"enumsAsConst"`
+	// FutureProofEnums adds '%future added value' to enums written as types
+	FutureProofEnums bool `json:"futureProofEnums"`
+	// EnumValues replaces enums with an enum imported from a module, or gives their values other strings
+	EnumValues map[string]EnumValues `json:"enumValues"`
+	// NamingConvention sets the case of enum keys, they are upper-cased by default
+	NamingConvention NamingConvention `json:"namingConvention"`
+}
+
+/*
+EnumValues maps an enum either to an enum of a module, given like ./enums#Role, or its values to other strings
+*/
+type EnumValues struct {
+	Import string
+	Values map[string]string
+}
+
+func (e *EnumValues) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &e.Import); err == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &e.Values); err != nil {
+		return errors.New("enumValues must be a module or an object of values")
+	}
+
+	return nil
+}
+
+/*
+NamingConvention is the naming convention of enum keys, given as a convention or as an object like upstream
+*/
+type NamingConvention struct {
+	EnumValues string
+}
+
+func (n *NamingConvention) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &n.EnumValues); err == nil {
+		return nil
+	}
+
+	var conventions struct {
+		EnumValues string `json:"enumValues"`
+	}
+	if err := json.Unmarshal(data, &conventions); err != nil {
+		return errors.New("namingConvention must be a convention or an object of conventions")
+	}
+
+	n.EnumValues = conventions.EnumValues
+	return nil
 }
 
 /*
-ConvertSchema converts a graphql schema to Typescript output
+ScalarType is the Typescript type of a scalar, given as a single type or as an object with an input and an output
+type like upstream
 */
-func ConvertSchema(schema *ast.Schema, output *strings.Builder) {
-	output.WriteString("/* Generated by faster-graphql-codegen on " + time.Now().Format(time.DateTime) + " */\n")
+type ScalarType struct {
+	Input  string `json:"input"`
+	Output string `json:"output"`
+}
+
+func (s *ScalarType) UnmarshalJSON(data []byte) error {
+	var singleType string
+	if err := json.Unmarshal(data, &singleType); err == nil {
+		s.Input = singleType
+		s.Output = singleType
+		return nil
+	}
 
+	type plainScalarType ScalarType
+	var scalarType plainScalarType
+	if err := json.Unmarshal(data, &scalarType); err != nil || scalarType.Input == "" || scalarType.Output == "" {
+		return errors.New("scalars must be a type or an object with an input and an output type")
+	}
+
+	*s = ScalarType(scalarType)
+	return nil
+}
+
+func init() {
+	Register(typescriptPlugin{})
+}
+
+type typescriptPlugin struct{}
+
+func (typescriptPlugin) Name() string {
+	return "typescript"
+}
+
+func (typescriptPlugin) DecodeOptions(config map[string]interface{}) (interface{}, error) {
+	options := TypescriptOptions{}
+	if err := decodeOptions(config, &options); err != nil {
+		return options, err
+	}
+
+	if options.NamingConvention.EnumValues != "" {
+		if _, err := ConvertCase("", options.NamingConvention.EnumValues); err != nil {
+			return options, err
+		}
+	}
+
+	return options, nil
+}
+
+func (typescriptPlugin) OutputExtensions() []string {
+	return nil
+}
+
+func (typescriptPlugin) Generate(task PluginTask, output io.Writer) error {
+	options, _ := task.Options.(TypescriptOptions)
+
+	converted := strings.Builder{}
+	ConvertSchema(task.Schema, &converted, task.Sort, task.SchemaFiles, options)
+
+	_, err := io.WriteString(output, converted.String())
+	return err
+}
+
+/*
+ConvertSchema converts a graphql schema to Typescript output, types are sorted by name if sortAlphabetically is set
+and kept in definition order otherwise, with the schema files in sourceOrder
+*/
+func ConvertSchema(schema *ast.Schema, output *strings.Builder, sortAlphabetically bool, sourceOrder []string, options TypescriptOptions) {
+	definitions := OrderedDefinitions(schema, sortAlphabetically, sourceOrder)
+
+	AddEnumImports(definitions, output, options)
 	AddBaseTypes(output)
-	knownScalars := AddScalars(schema, output)
+	knownScalars := AddScalars(definitions, output, options)
 
-	for _, definition := range schema.Types {
+	for _, definition := range definitions {
 		if definition.BuiltIn {
 			continue
 		}
 
-		err := ConvertDefinition(definition, output, knownScalars)
+		err := ConvertDefinition(definition, output, knownScalars, options)
 		if err != nil {
 			slog.Error(err.Error(), "kind", definition.Kind, "name", definition.Name)
 		} else {
@@ -36,31 +165,39 @@ func ConvertSchema(schema *ast.Schema, output *strings.Builder) {
 }
 
 var builtInScalars = map[string]string{
-	"String": "string",
+	"ID":      "string",
+	"String":  "string",
+	"Boolean": "boolean",
+	"Int":     "number",
+	"Float":   "number",
 }
 
 /*
-AddScalars parses a schema and outputs a Scalars type, it also returns a list of scalars it found
+builtInScalarOrder is the order upstream lists built-in scalars in, before any custom scalars
 */
-func AddScalars(schema *ast.Schema, output *strings.Builder) []*ast.Definition {
+var builtInScalarOrder = []string{"ID", "String", "Boolean", "Int", "Float"}
+
+/*
+AddScalars parses a list of definitions and outputs a Scalars type, it also returns a list of scalars it found.
+Scalars given in the options override the built-in types, other custom scalars get the default scalar type.
+*/
+func AddScalars(definitions []*ast.Definition, output *strings.Builder, options TypescriptOptions) []*ast.Definition {
 	output.WriteString("/** All built-in and custom scalars, mapped to their actual values */\n")
 	output.WriteString("export type Scalars = {\n")
 
 	var scalars []*ast.Definition
 
-	for _, definition := range schema.Types {
+	orderedScalars := slices.Clone(definitions)
+	slices.SortStableFunc(orderedScalars, func(a, b *ast.Definition) int {
+		return scalarRank(a) - scalarRank(b)
+	})
+
+	for _, definition := range orderedScalars {
 		if definition.Kind == ast.Scalar {
 			scalars = append(scalars, definition)
 
-			output.WriteString("\t" + definition.Name + ": ")
-
-			if knownScalarType, ok := builtInScalars[definition.Name]; ok {
-				output.WriteString(knownScalarType)
-			} else {
-				output.WriteString("any")
-			}
-
-			output.WriteString(";\n")
+			scalarType := scalarTypeOf(definition.Name, options.Scalars, options.DefaultScalarType)
+			output.WriteString("\t" + definition.Name + ": { input: " + scalarType.Input + "; output: " + scalarType.Output + "; }\n")
 		}
 	}
 
@@ -69,15 +206,39 @@ func AddScalars(schema *ast.Schema, output *strings.Builder) []*ast.Definition {
 	return scalars
 }
 
+func scalarTypeOf(name string, scalars map[string]ScalarType, defaultScalarType string) ScalarType {
+	if scalarType, ok := scalars[name]; ok {
+		return scalarType
+	}
+
+	if builtInType, ok := builtInScalars[name]; ok {
+		return ScalarType{Input: builtInType, Output: builtInType}
+	}
+
+	if defaultScalarType != "" {
+		return ScalarType{Input: defaultScalarType, Output: defaultScalarType}
+	}
+
+	return ScalarType{Input: "any", Output: "any"}
+}
+
+func scalarRank(definition *ast.Definition) int {
+	if index := slices.Index(builtInScalarOrder, definition.Name); index != -1 {
+		return index
+	}
+
+	return len(builtInScalarOrder)
+}
+
 func AddBaseTypes(output *strings.Builder) {
 	output.WriteString("export type Maybe<T> = T | null;\nexport type InputMaybe<T> = Maybe<T>;\nexport type Exact<T extends { [key: string]: unknown }> = { [K in keyof T]: T[K] };\nexport type MakeOptional<T, K extends keyof T> = Omit<T, K> & { [SubKey in K]?: Maybe<T[SubKey]> };\nexport type MakeMaybe<T, K extends keyof T> = Omit<T, K> & { [SubKey in K]: Maybe<T[SubKey]> };\nexport type MakeEmpty<T extends { [key: string]: unknown }, K extends keyof T> = { [_ in K]?: never };\nexport type Incremental<T> = T | { [P in keyof T]?: P extends ' $fragmentName' | '__typename' ? T[P] : never };")
 	output.WriteString("\n")
 }
 
-func ConvertDefinition(definition *ast.Definition, output *strings.Builder, knownScalars []*ast.Definition) error {
+func ConvertDefinition(definition *ast.Definition, output *strings.Builder, knownScalars []*ast.Definition, options TypescriptOptions) error {
 	switch definition.Kind {
 	case ast.Enum:
-		ConvertEnum(definition, output)
+		ConvertEnum(definition, output, options)
 	case ast.Union:
 		ConvertUnion(definition, output)
 	case ast.Interface:
@@ -94,24 +255,120 @@ func ConvertDefinition(definition *ast.Definition, output *strings.Builder, know
 	return nil
 }
 
-func ConvertEnum(definition *ast.Definition, output *strings.Builder) {
+/*
+AddEnumImports imports the enums that enumValues maps to a module, like upstream does at the top of the output
+*/
+func AddEnumImports(definitions []*ast.Definition, output *strings.Builder, options TypescriptOptions) {
+	for _, definition := range definitions {
+		enumValues, ok := options.EnumValues[definition.Name]
+		if definition.Kind != ast.Enum || !ok || enumValues.Import == "" {
+			continue
+		}
+
+		module, identifier, found := strings.Cut(enumValues.Import, "#")
+		enumName := ToCamel(definition.Name)
+
+		if !found || identifier == enumName {
+			output.WriteString("import { " + enumName + " } from '" + module + "';\n")
+		} else {
+			output.WriteString("import { " + identifier + " as " + enumName + " } from '" + module + "';\n")
+		}
+	}
+}
+
+func ConvertEnum(definition *ast.Definition, output *strings.Builder, options TypescriptOptions) {
 	enumName := ToCamel(definition.Name)
+	enumValues := options.EnumValues[definition.Name]
 
 	WriteComment(definition, output)
 
-	output.WriteString("export enum " + enumName + " {\n")
+	// imported enums are exported again in place of the enum
+	if enumValues.Import != "" {
+		output.WriteString("export { " + enumName + " };\n")
+		return
+	}
+
+	if options.EnumsAsTypes {
+		literals := make([]string, 0, len(definition.EnumValues)+1)
+		for _, enumValue := range definition.EnumValues {
+			literals = append(literals, "\t| "+enumLiteral(enumValue.Name, enumValues))
+		}
+		if options.FutureProofEnums {
+			literals = append(literals, "\t| '%future added value'")
+		}
+
+		output.WriteString("export type " + enumName + " =\n" + strings.Join(literals, "\n") + ";\n")
+		return
+	}
+
+	if options.EnumsAsConst {
+		output.WriteString("export const " + enumName + " = {\n")
+	} else {
+		output.WriteString("export enum " + enumName + " {\n")
+	}
+
+	separator := " = "
+	if options.EnumsAsConst {
+		separator = ": "
+	}
 
 	for i, enumValue := range definition.EnumValues {
-		enumName := enumValue.Name
-		enumKey := ToUpper(enumValue.Name)
-		output.WriteString("\t" + enumKey + " = '" + enumName + "'")
+		enumKey := enumKey(enumValue.Name, options)
+		output.WriteString("\t" + enumKey + separator + enumLiteral(enumValue.Name, enumValues))
 
 		if i != len(definition.EnumValues)-1 {
 			output.WriteString(",")
 		}
 		output.WriteString("\n")
 	}
-	output.WriteString("}\n")
+
+	if options.EnumsAsConst {
+		output.WriteString("} as const;\n")
+		output.WriteString("export type " + enumName + " = typeof " + enumName + "[keyof typeof " + enumName + "];\n")
+	} else {
+		output.WriteString("}\n")
+	}
+}
+
+/*
+enumKey converts an enum value to the key it is written with, keys that are not identifiers, such as the my-value of
+paramCase, are quoted
+*/
+func enumKey(name string, options TypescriptOptions) string {
+	key := ToUpper(name)
+	if options.NamingConvention.EnumValues != "" {
+		// the convention is checked when options are decoded
+		key, _ = ConvertCase(name, options.NamingConvention.EnumValues)
+	}
+
+	if !isIdentifier(key) {
+		return stringLiteral(key)
+	}
+
+	return key
+}
+
+func enumLiteral(name string, enumValues EnumValues) string {
+	if value, ok := enumValues.Values[name]; ok {
+		name = value
+	}
+
+	return stringLiteral(name)
+}
+
+func stringLiteral(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
+
+func isIdentifier(name string) bool {
+	for i, character := range name {
+		isLetter := character == '_' || character == '$' || (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z')
+		if !isLetter && (i == 0 || character < '0' || character > '9') {
+			return false
+		}
+	}
+
+	return name != ""
 }
 
 func WriteComment(definition *ast.Definition, output *strings.Builder) {
@@ -208,6 +465,10 @@ func ConvertObject(definition *ast.Definition, output *strings.Builder, knownSca
 	output.WriteString("}\n")
 
 	for _, field := range definition.Fields {
+		if field.Name == "__type" || field.Name == "__schema" {
+			continue
+		}
+
 		WriteFieldArguments(field, output, knownScalars, interfaceName)
 	}
 }
@@ -250,6 +511,12 @@ func ConvertInputObject(definition *ast.Definition, output *strings.Builder, kno
 func AddFieldType(definition *ast.FieldDefinition, output *strings.Builder, maybeType string, knownScalars []*ast.Definition) {
 	isNullable := !definition.Type.NonNull
 
+	// fields of input objects use the input type of scalars
+	scalarKind := "output"
+	if maybeType == "InputMaybe" {
+		scalarKind = "input"
+	}
+
 	if isNullable {
 		output.WriteString("?: " + maybeType + "<")
 	} else {
@@ -278,7 +545,7 @@ func AddFieldType(definition *ast.FieldDefinition, output *strings.Builder, mayb
 	}
 
 	if isScalarKnown {
-		output.WriteString("Scalars['" + definition.Type.Name() + "']")
+		output.WriteString("Scalars['" + definition.Type.Name() + "']['" + scalarKind + "']")
 	} else {
 		output.WriteString(ToCamel(definition.Type.Name()))
 	}
@@ -329,7 +596,7 @@ func AddArgumentType(definition *ast.ArgumentDefinition, output *strings.Builder
 	}
 
 	if isScalarKnown {
-		output.WriteString("Scalars['" + definition.Type.Name() + "']")
+		output.WriteString("Scalars['" + definition.Type.Name() + "']['input']")
 	} else {
 		output.WriteString(ToCamel(definition.Type.Name()))
 	}
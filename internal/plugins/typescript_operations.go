@@ -0,0 +1,434 @@
+package plugins
+
+import (
+	"fmt"
+	"github.com/vektah/gqlparser/v2/ast"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+TypescriptOperationsOptions are the options of the typescript-operations plugin
+*/
+type TypescriptOperationsOptions struct {
+	// SkipTypename leaves out __typename unless it is selected
+	SkipTypename bool `json:"skipTypename"`
+	// NonOptionalTypename makes __typename required even if it is not selected
+	NonOptionalTypename bool `json:"nonOptionalTypename"`
+	// InlineFragmentTypes is inline to merge the fields of spread fragments into the result, or mask to refer to the
+	// fragments instead, so their fields are only read through the fragment
+	InlineFragmentTypes string `json:"inlineFragmentTypes"`
+}
+
+func init() {
+	Register(typescriptOperationsPlugin{})
+}
+
+/*
+typescriptOperationsPlugin writes the result and variables types of the operations and fragments of the documents,
+like @graphql-codegen/typescript-operations. The types refer to the output of the typescript plugin.
+*/
+type typescriptOperationsPlugin struct{}
+
+func (typescriptOperationsPlugin) Name() string {
+	return "typescript-operations"
+}
+
+func (typescriptOperationsPlugin) DecodeOptions(config map[string]interface{}) (interface{}, error) {
+	options := TypescriptOperationsOptions{}
+	if err := decodeOptions(config, &options); err != nil {
+		return options, err
+	}
+
+	if options.InlineFragmentTypes != "" && options.InlineFragmentTypes != "inline" && options.InlineFragmentTypes != "mask" {
+		return options, fmt.Errorf("unknown inlineFragmentTypes %s, expected inline or mask", options.InlineFragmentTypes)
+	}
+
+	return options, nil
+}
+
+func (typescriptOperationsPlugin) OutputExtensions() []string {
+	return nil
+}
+
+func (typescriptOperationsPlugin) Generate(task PluginTask, output io.Writer) error {
+	options, _ := task.Options.(TypescriptOperationsOptions)
+
+	converted := strings.Builder{}
+	ConvertOperations(task.Schema, task.Documents, &converted, options)
+
+	_, err := io.WriteString(output, converted.String())
+	return err
+}
+
+/*
+ConvertOperations converts the operations and fragments of documents to Typescript types, in the order they are
+written in. Anonymous operations are numbered like upstream numbers them, and a named operation or fragment written
+in several documents is converted once.
+*/
+func ConvertOperations(schema *ast.Schema, documents []*Document, output *strings.Builder, options TypescriptOperationsOptions) {
+	printer := operationsPrinter{
+		schema:    schema,
+		options:   options,
+		fragments: AllFragments(documents),
+	}
+
+	unnamedOperations := 0
+	converted := make(map[string]bool)
+	for _, document := range documents {
+		for _, definition := range documentDefinitions(document.Document) {
+			switch d := definition.(type) {
+			case *ast.OperationDefinition:
+				root := operationRoot(schema, d.Operation)
+				if root == nil {
+					continue
+				}
+
+				name := OperationTypeName(d)
+				if d.Name == "" {
+					unnamedOperations++
+					name = "Unnamed_" + strconv.Itoa(unnamedOperations) + "_" + name
+				}
+				if converted[name] {
+					continue
+				}
+				converted[name] = true
+
+				output.WriteString("export type " + name + "Variables = " + printer.variables(d.VariableDefinitions) + ";\n\n")
+				output.WriteString("export type " + name + " = " + printer.selectionSet(root, d.SelectionSet) + ";\n\n")
+			case *ast.FragmentDefinition:
+				definition := schema.Types[d.TypeCondition]
+				if definition == nil || converted[FragmentTypeName(d)] {
+					continue
+				}
+				converted[FragmentTypeName(d)] = true
+
+				fragmentType := printer.selectionSet(definition, d.SelectionSet)
+				if options.InlineFragmentTypes == "mask" {
+					if len(printer.possibleTypes(definition, d.SelectionSet)) > 1 {
+						fragmentType = "(" + fragmentType + ")"
+					}
+					fragmentType += " & { ' $fragmentName'?: '" + FragmentTypeName(d) + "' }"
+				}
+
+				output.WriteString("export type " + FragmentTypeName(d) + " = " + fragmentType + ";\n\n")
+			}
+		}
+	}
+}
+
+/*
+OperationTypeName names the result type of an operation, e.g. GetUserQuery. Anonymous operations are named after
+their kind only.
+*/
+func OperationTypeName(operation *ast.OperationDefinition) string {
+	return ToCamel(operation.Name) + ToCamel(string(operation.Operation))
+}
+
+/*
+FragmentTypeName names the type of a fragment, e.g. UserFieldsFragment
+*/
+func FragmentTypeName(fragment *ast.FragmentDefinition) string {
+	return ToCamel(fragment.Name) + "Fragment"
+}
+
+/*
+documentDefinitions returns the operations and fragments of a document in the order they are written in
+*/
+func documentDefinitions(document *ast.QueryDocument) []interface{} {
+	type positioned struct {
+		start      int
+		definition interface{}
+	}
+
+	var definitions []positioned
+	for _, operation := range document.Operations {
+		definitions = append(definitions, positioned{operation.Position.Start, operation})
+	}
+	for _, fragment := range document.Fragments {
+		definitions = append(definitions, positioned{fragment.Position.Start, fragment})
+	}
+
+	slices.SortStableFunc(definitions, func(a, b positioned) int {
+		return a.start - b.start
+	})
+
+	ordered := make([]interface{}, 0, len(definitions))
+	for _, definition := range definitions {
+		ordered = append(ordered, definition.definition)
+	}
+
+	return ordered
+}
+
+func operationRoot(schema *ast.Schema, operation ast.Operation) *ast.Definition {
+	switch operation {
+	case ast.Query:
+		return schema.Query
+	case ast.Mutation:
+		return schema.Mutation
+	case ast.Subscription:
+		return schema.Subscription
+	}
+
+	return nil
+}
+
+type operationsPrinter struct {
+	schema    *ast.Schema
+	options   TypescriptOperationsOptions
+	fragments ast.FragmentDefinitionList
+}
+
+/*
+selectedField is a field of a selection set by response key, merged from every selection of it
+*/
+type selectedField struct {
+	key        string
+	name       string
+	selections ast.SelectionSet
+	// optional is set if every selection of the field is conditional
+	optional bool
+	// fragment is the type name of a masked fragment spread, which is not a field
+	fragment string
+}
+
+/*
+selectionSet prints the type a selection set results in, a union of the possible types of an abstract type
+*/
+func (p *operationsPrinter) selectionSet(parent *ast.Definition, selections ast.SelectionSet) string {
+	types := p.possibleTypes(parent, selections)
+	if len(types) == 0 {
+		return "never"
+	}
+
+	return strings.Join(types, " | ")
+}
+
+/*
+possibleTypes prints the distinct types a selection set may result in
+*/
+func (p *operationsPrinter) possibleTypes(parent *ast.Definition, selections ast.SelectionSet) []string {
+	if parent.Kind == ast.Object {
+		return []string{p.objectType(parent, selections)}
+	}
+
+	var types []string
+	for _, possibleType := range p.schema.GetPossibleTypes(parent) {
+		objectType := p.objectType(possibleType, selections)
+		if !slices.Contains(types, objectType) {
+			types = append(types, objectType)
+		}
+	}
+
+	return types
+}
+
+func (p *operationsPrinter) objectType(object *ast.Definition, selections ast.SelectionSet) string {
+	fields := p.collectFields(object, selections, false, nil, nil)
+
+	var printed []string
+
+	typenameIndex := slices.IndexFunc(fields, func(field *selectedField) bool {
+		return field.key == "__typename" && field.name == "__typename"
+	})
+	switch {
+	case typenameIndex != -1:
+		printed = append(printed, "__typename"+optionalMark(fields[typenameIndex].optional)+": '"+object.Name+"'")
+	case !p.options.SkipTypename:
+		printed = append(printed, "__typename"+optionalMark(!p.options.NonOptionalTypename)+": '"+object.Name+"'")
+	}
+
+	var fragmentRefs []string
+	for index, field := range fields {
+		if index == typenameIndex {
+			continue
+		}
+
+		if field.fragment != "" {
+			fragmentRefs = append(fragmentRefs, "'"+field.fragment+"': "+field.fragment)
+			continue
+		}
+
+		if field.name == "__typename" {
+			printed = append(printed, field.key+optionalMark(field.optional)+": '"+object.Name+"'")
+			continue
+		}
+
+		definition := object.Fields.ForName(field.name)
+		if definition == nil {
+			continue
+		}
+
+		optional := field.optional || !definition.Type.NonNull
+		printed = append(printed, field.key+optionalMark(optional)+": "+p.fieldType(definition.Type, field.selections))
+	}
+
+	objectType := "{}"
+	if len(printed) > 0 {
+		objectType = "{ " + strings.Join(printed, ", ") + " }"
+	}
+
+	if len(fragmentRefs) > 0 {
+		return "(" + objectType + " & { ' $fragmentRefs'?: { " + strings.Join(fragmentRefs, ";") + " } })"
+	}
+
+	return objectType
+}
+
+/*
+collectFields collects the fields selected on an object type, including those of fragments that apply to it.
+Fields are optional if they, or a fragment they are selected in, have @include or @skip.
+*/
+func (p *operationsPrinter) collectFields(
+	object *ast.Definition,
+	selections ast.SelectionSet,
+	optional bool,
+	spreads []string,
+	fields []*selectedField,
+) []*selectedField {
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			fieldOptional := optional || isConditional(s.Directives)
+
+			key := s.Alias
+			if key == "" {
+				key = s.Name
+			}
+
+			index := slices.IndexFunc(fields, func(field *selectedField) bool {
+				return field.key == key
+			})
+			if index == -1 {
+				fields = append(fields, &selectedField{
+					key:        key,
+					name:       s.Name,
+					selections: slices.Clone(s.SelectionSet),
+					optional:   fieldOptional,
+				})
+				continue
+			}
+
+			fields[index].selections = append(fields[index].selections, s.SelectionSet...)
+			fields[index].optional = fields[index].optional && fieldOptional
+		case *ast.InlineFragment:
+			if s.TypeCondition == "" || p.appliesTo(s.TypeCondition, object) {
+				fields = p.collectFields(object, s.SelectionSet, optional || isConditional(s.Directives), spreads, fields)
+			}
+		case *ast.FragmentSpread:
+			// cyclic spreads are a validation error, they are not followed again
+			fragment := p.fragments.ForName(s.Name)
+			if fragment == nil || slices.Contains(spreads, s.Name) {
+				continue
+			}
+
+			if !p.appliesTo(fragment.TypeCondition, object) {
+				continue
+			}
+
+			// masked fragments are referred to by name, their fields are not selected
+			if p.options.InlineFragmentTypes == "mask" {
+				name := FragmentTypeName(fragment)
+				if !slices.ContainsFunc(fields, func(field *selectedField) bool { return field.fragment == name }) {
+					fields = append(fields, &selectedField{fragment: name})
+				}
+				continue
+			}
+
+			fields = p.collectFields(object, fragment.SelectionSet, optional || isConditional(s.Directives),
+				append(slices.Clone(spreads), s.Name), fields)
+		}
+	}
+
+	return fields
+}
+
+/*
+appliesTo checks if a fragment with a type condition is selected on an object type
+*/
+func (p *operationsPrinter) appliesTo(typeCondition string, object *ast.Definition) bool {
+	if typeCondition == object.Name {
+		return true
+	}
+
+	definition := p.schema.Types[typeCondition]
+	return definition != nil && definition.IsAbstractType() && slices.Contains(p.schema.GetPossibleTypes(definition), object)
+}
+
+func (p *operationsPrinter) fieldType(fieldType *ast.Type, selections ast.SelectionSet) string {
+	var printed string
+	if fieldType.Elem != nil {
+		printed = "Array<" + p.fieldType(fieldType.Elem, selections) + ">"
+	} else {
+		definition := p.schema.Types[fieldType.NamedType]
+
+		switch {
+		case definition == nil:
+			printed = "any"
+		case definition.Kind == ast.Scalar:
+			printed = "Scalars['" + definition.Name + "']['output']"
+		case definition.Kind == ast.Enum:
+			printed = ToCamel(definition.Name)
+		default:
+			printed = p.selectionSet(definition, selections)
+		}
+	}
+
+	if !fieldType.NonNull {
+		printed += " | null"
+	}
+
+	return printed
+}
+
+func (p *operationsPrinter) variables(variables ast.VariableDefinitionList) string {
+	if len(variables) == 0 {
+		return "Exact<{ [key: string]: never; }>"
+	}
+
+	printed := strings.Builder{}
+	printed.WriteString("Exact<{\n")
+	for _, variable := range variables {
+		optional := !variable.Type.NonNull || variable.DefaultValue != nil
+		printed.WriteString("\t" + variable.Variable + optionalMark(optional) + ": " + p.inputType(variable.Type) + ";\n")
+	}
+	printed.WriteString("}>")
+
+	return printed.String()
+}
+
+/*
+inputType prints the type of a variable, lists also accept a single item like GraphQL input coercion does
+*/
+func (p *operationsPrinter) inputType(inputType *ast.Type) string {
+	var printed string
+	if inputType.Elem != nil {
+		item := p.inputType(inputType.Elem)
+		printed = "Array<" + item + "> | " + item
+	} else if definition := p.schema.Types[inputType.NamedType]; definition != nil && definition.Kind == ast.Scalar {
+		printed = "Scalars['" + definition.Name + "']['input']"
+	} else {
+		printed = ToCamel(inputType.NamedType)
+	}
+
+	if !inputType.NonNull {
+		printed = "InputMaybe<" + printed + ">"
+	}
+
+	return printed
+}
+
+func isConditional(directives ast.DirectiveList) bool {
+	return directives.ForName("include") != nil || directives.ForName("skip") != nil
+}
+
+func optionalMark(optional bool) string {
+	if optional {
+		return "?"
+	}
+
+	return ""
+}
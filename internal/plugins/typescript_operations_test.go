@@ -0,0 +1,195 @@
+package plugins
+
+import (
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"strings"
+	"testing"
+)
+
+const testOperationsSchema = `
+scalar DateTime
+
+type Query {
+	user(id: ID!): User
+	search(term: String!, first: Int = 10): [SearchResult!]!
+	node(id: ID!): Node
+}
+
+type Mutation {
+	updateUser(input: UpdateUserInput!): User!
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	name: String
+	role: Role!
+	tags: [String]
+	createdAt: DateTime!
+}
+
+type Post implements Node {
+	id: ID!
+	title: String!
+}
+
+union SearchResult = User | Post
+
+enum Role {
+	ADMIN
+	GUEST
+}
+
+input UpdateUserInput {
+	id: ID!
+	name: String
+}
+`
+
+func testDocuments(t *testing.T, inputs ...string) []*Document {
+	t.Helper()
+
+	var documents []*Document
+	for i, input := range inputs {
+		source := &ast.Source{Name: "document" + string(rune('a'+i)) + ".graphql", Input: input}
+		document, err := parser.ParseQuery(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		documents = append(documents, &Document{File: source.Name, Source: source, Raw: input, Document: document})
+	}
+
+	return documents
+}
+
+// TestConvertOperations tests the types of selections, variables and fragments
+func TestConvertOperations(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: testOperationsSchema})
+
+	tests := []struct {
+		name     string
+		document string
+		options  TypescriptOperationsOptions
+		expected string
+	}{
+		{
+			name:     "Query",
+			document: `query GetUser($id: ID!) { user(id: $id) { id name role tags createdAt } }`,
+			expected: "export type GetUserQueryVariables = Exact<{\n\tid: Scalars['ID']['input'];\n}>;\n\n" +
+				"export type GetUserQuery = { __typename?: 'Query', user?: { __typename?: 'User', id: Scalars['ID']['output'], " +
+				"name?: Scalars['String']['output'] | null, role: Role, tags?: Array<Scalars['String']['output'] | null> | null, createdAt: Scalars['DateTime']['output'] } | null };\n\n",
+		},
+		{
+			name:     "AliasesAndTypename",
+			document: `query { me: user(id: 1) { __typename userId: id } }`,
+			expected: "export type Unnamed_1_QueryVariables = Exact<{ [key: string]: never; }>;\n\n" +
+				"export type Unnamed_1_Query = { __typename?: 'Query', me?: { __typename: 'User', userId: Scalars['ID']['output'] } | null };\n\n",
+		},
+		{
+			name:     "InlineFragmentsOnUnion",
+			document: `query Search($term: String!, $first: Int) { search(term: $term, first: $first) { ... on User { name } ... on Post { title } } }`,
+			options:  TypescriptOperationsOptions{SkipTypename: true},
+			expected: "export type SearchQueryVariables = Exact<{\n\tterm: Scalars['String']['input'];\n" +
+				"\tfirst?: InputMaybe<Scalars['Int']['input']>;\n}>;\n\n" +
+				"export type SearchQuery = { search: Array<{ name?: Scalars['String']['output'] | null } | { title: Scalars['String']['output'] }> };\n\n",
+		},
+		{
+			name:     "FragmentsOnInterface",
+			document: `query Node { node(id: 1) { id ...PostFields } } fragment PostFields on Post { title }`,
+			expected: "export type NodeQuery = { __typename?: 'Query', node?: { __typename?: 'User', id: Scalars['ID']['output'] } | " +
+				"{ __typename?: 'Post', id: Scalars['ID']['output'], title: Scalars['String']['output'] } | null };\n\n" +
+				"export type PostFieldsFragment = { __typename?: 'Post', title: Scalars['String']['output'] };\n\n",
+		},
+		{
+			name:     "IncludeAndSkip",
+			document: `query User($full: Boolean!) { user(id: 1) { id @skip(if: $full) role @include(if: $full) ... @include(if: $full) { createdAt } } }`,
+			options:  TypescriptOperationsOptions{NonOptionalTypename: true},
+			expected: "user?: { __typename: 'User', id?: Scalars['ID']['output'], role?: Role, createdAt?: Scalars['DateTime']['output'] } | null",
+		},
+		{
+			name:     "MaskedFragments",
+			document: `query Node { node(id: 1) { id ...PostFields ...NodeFields } } fragment PostFields on Post { title } fragment NodeFields on Node { id ... on Post { title } }`,
+			options:  TypescriptOperationsOptions{SkipTypename: true, InlineFragmentTypes: "mask"},
+			expected: "export type NodeQuery = { node?: ({ id: Scalars['ID']['output'] } & { ' $fragmentRefs'?: { 'NodeFieldsFragment': NodeFieldsFragment } }) | " +
+				"({ id: Scalars['ID']['output'] } & { ' $fragmentRefs'?: { 'PostFieldsFragment': PostFieldsFragment;'NodeFieldsFragment': NodeFieldsFragment } }) | null };\n\n" +
+				"export type PostFieldsFragment = { title: Scalars['String']['output'] } & { ' $fragmentName'?: 'PostFieldsFragment' };\n\n" +
+				"export type NodeFieldsFragment = ({ id: Scalars['ID']['output'] } | { id: Scalars['ID']['output'], title: Scalars['String']['output'] }) & { ' $fragmentName'?: 'NodeFieldsFragment' };\n\n",
+		},
+		{
+			name:     "Mutation",
+			document: `mutation UpdateUser($input: UpdateUserInput!, $ids: [ID!]) { updateUser(input: $input) { id } }`,
+			expected: "export type UpdateUserMutationVariables = Exact<{\n\tinput: UpdateUserInput;\n" +
+				"\tids?: InputMaybe<Array<Scalars['ID']['input']> | Scalars['ID']['input']>;\n}>;\n\n" +
+				"export type UpdateUserMutation = { __typename?: 'Mutation', updateUser: { __typename?: 'User', id: Scalars['ID']['output'] } };\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			documents := testDocuments(t, tt.document)
+			if errs := ValidateDocuments(schema, documents); len(errs) > 0 {
+				t.Fatal(errs)
+			}
+
+			output := strings.Builder{}
+			ConvertOperations(schema, documents, &output, tt.options)
+
+			if !strings.Contains(output.String(), tt.expected) {
+				t.Errorf("ConvertOperations() = %s, expected it to contain %s", output.String(), tt.expected)
+			}
+		})
+	}
+}
+
+// TestConvertOperationsDuplicates tests that a document written in several files is converted once
+func TestConvertOperationsDuplicates(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: testOperationsSchema})
+	document := `query GetUser { user(id: 1) { ...UserFields } } fragment UserFields on User { id }`
+
+	output := strings.Builder{}
+	ConvertOperations(schema, testDocuments(t, document, document), &output, TypescriptOperationsOptions{})
+
+	for _, name := range []string{"export type GetUserQueryVariables ", "export type GetUserQuery ", "export type UserFieldsFragment "} {
+		if count := strings.Count(output.String(), name); count != 1 {
+			t.Errorf("ConvertOperations() wrote %q %d times, expected once", name, count)
+		}
+	}
+}
+
+// TestValidateDocuments tests that fragments are shared between documents and invalid documents are reported
+func TestValidateDocuments(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: testOperationsSchema})
+
+	documents := testDocuments(t,
+		`query GetUser { user(id: 1) { ...UserFields } }`,
+		`fragment UserFields on User { id } fragment Unused on Post { id }`,
+	)
+	if errs := ValidateDocuments(schema, documents); len(errs) > 0 {
+		t.Errorf("ValidateDocuments() = %v, expected no errors", errs)
+	}
+
+	documents = testDocuments(t, `query GetUser { user(id: 1) { email } }`)
+	errs := ValidateDocuments(schema, documents)
+	if len(errs) != 1 || errs[0].Rule != "FieldsOnCorrectType" {
+		t.Errorf("ValidateDocuments() = %v, expected an unknown field error", errs)
+	}
+
+	documents = testDocuments(t,
+		`query Foo { user(id: 1) { id } }`,
+		"fragment UserFields on User { id }\n\nquery Foo { user(id: 2) { id } }",
+		`query Foo { user(id: 1) { id } }`,
+	)
+	errs = ValidateDocuments(schema, documents)
+	if len(errs) != 1 || errs[0].Rule != "UniqueOperationNames" {
+		t.Fatalf("ValidateDocuments() = %v, expected one duplicate operation error", errs)
+	}
+	if file := errs[0].Extensions["file"]; file != "documentb.graphql" || errs[0].Locations[0].Line != 3 {
+		t.Errorf("ValidateDocuments() reported the duplicate at %v:%d, expected the second definition", file, errs[0].Locations[0].Line)
+	}
+}
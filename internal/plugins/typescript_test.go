@@ -0,0 +1,297 @@
+package plugins
+
+import (
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testSchema = `
+type Query {
+	user(id: ID!): User
+	search(term: String!): [SearchResult!]!
+}
+
+type User {
+	name: String
+	id: ID!
+}
+
+enum Role {
+	GUEST
+	ADMIN
+}
+
+union SearchResult = User
+`
+
+var exportedNamePattern = regexp.MustCompile(`export (?:type|enum) (\w+)`)
+
+func exportedNames(output string) []string {
+	var names []string
+	for _, match := range exportedNamePattern.FindAllStringSubmatch(output, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// TestConvertSchemaOrdering tests that output is stable, either sorted or in definition order
+func TestConvertSchemaOrdering(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: testSchema})
+
+	tests := []struct {
+		name               string
+		sortAlphabetically bool
+		expected           []string
+	}{
+		{
+			name:               "Sorted",
+			sortAlphabetically: true,
+			expected:           []string{"Query", "QuerySearchArgs", "QueryUserArgs", "Role", "SearchResult", "User"},
+		},
+		{
+			name:               "DefinitionOrder",
+			sortAlphabetically: false,
+			expected:           []string{"Query", "QueryUserArgs", "QuerySearchArgs", "User", "Role", "SearchResult"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var outputs []string
+			for range 10 {
+				output := strings.Builder{}
+				ConvertSchema(schema, &output, tt.sortAlphabetically, nil, TypescriptOptions{})
+				outputs = append(outputs, output.String())
+			}
+
+			for _, output := range outputs[1:] {
+				if output != outputs[0] {
+					t.Fatalf("ConvertSchema() output differs between runs")
+				}
+			}
+
+			names := slices.DeleteFunc(exportedNames(outputs[0]), func(name string) bool {
+				return slices.Contains([]string{"Maybe", "InputMaybe", "Exact", "MakeOptional", "MakeMaybe", "MakeEmpty", "Incremental", "Scalars"}, name)
+			})
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("ConvertSchema() exported %v, expected %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+// TestConvertSchemaSourceOrder tests that types of several schema files are kept in the order of the files
+func TestConvertSchemaSourceOrder(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(
+		&ast.Source{Name: "a.graphql", Input: "type A { id: ID }\nextend type Query { a: A }"},
+		&ast.Source{Name: "b.graphql", Input: "type Query { b: B }\ntype B { id: ID }"},
+	)
+
+	tests := []struct {
+		name        string
+		sourceOrder []string
+		expected    []string
+	}{
+		{name: "SourceOrder", sourceOrder: []string{"b.graphql", "a.graphql"}, expected: []string{"Query", "B", "A"}},
+		{name: "SourceName", expected: []string{"A", "Query", "B"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, definition := range OrderedDefinitions(schema, false, tt.sourceOrder) {
+				if !definition.BuiltIn {
+					names = append(names, definition.Name)
+				}
+			}
+
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("OrderedDefinitions() = %v, expected %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddScalarsOrder(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: "scalar DateTime\ntype Query { now: DateTime }"})
+
+	output := strings.Builder{}
+	AddScalars(OrderedDefinitions(schema, true, nil), &output, TypescriptOptions{})
+
+	var scalarNames []string
+	for _, line := range strings.Split(output.String(), "\n") {
+		if name, _, ok := strings.Cut(strings.TrimSpace(line), ":"); ok {
+			scalarNames = append(scalarNames, name)
+		}
+	}
+
+	expected := []string{"ID", "String", "Boolean", "Int", "Float", "DateTime"}
+	if !slices.Equal(scalarNames, expected) {
+		t.Errorf("AddScalars() = %v, expected %v", scalarNames, expected)
+	}
+}
+
+// TestAddScalarsTypes tests the types of built-in scalars and the scalars options
+func TestAddScalarsTypes(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name:  "schema.graphql",
+		Input: "scalar DateTime\nscalar JSON\nscalar Upload\ntype Query { now(at: DateTime): DateTime, data: JSON }",
+	})
+
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected []string
+	}{
+		{
+			name: "Defaults",
+			expected: []string{
+				"ID: { input: string; output: string; }",
+				"Boolean: { input: boolean; output: boolean; }",
+				"Int: { input: number; output: number; }",
+				"Float: { input: number; output: number; }",
+				"DateTime: { input: any; output: any; }",
+			},
+		},
+		{
+			name: "Scalars",
+			config: map[string]interface{}{
+				"scalars": map[string]interface{}{
+					"DateTime": "Date",
+					"Upload":   map[string]interface{}{"input": "File", "output": "never"},
+					"ID":       "number",
+				},
+				"defaultScalarType": "unknown",
+			},
+			expected: []string{
+				"ID: { input: number; output: number; }",
+				"DateTime: { input: Date; output: Date; }",
+				"Upload: { input: File; output: never; }",
+				"JSON: { input: unknown; output: unknown; }",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := (typescriptPlugin{}).DecodeOptions(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			output := strings.Builder{}
+			ConvertSchema(schema, &output, true, nil, options.(TypescriptOptions))
+
+			for _, line := range tt.expected {
+				if !strings.Contains(output.String(), "\t"+line+"\n") {
+					t.Errorf("ConvertSchema() output does not contain %s", line)
+				}
+			}
+		})
+	}
+
+	converted := strings.Builder{}
+	ConvertSchema(schema, &converted, true, nil, TypescriptOptions{})
+	if !strings.Contains(converted.String(), "now?: Maybe<Scalars['DateTime']['output']>;") {
+		t.Errorf("ConvertSchema() does not use the output type of scalars in fields")
+	}
+	if !strings.Contains(converted.String(), "at?: Maybe<Scalars['DateTime']['input']>;") {
+		t.Errorf("ConvertSchema() does not use the input type of scalars in arguments")
+	}
+
+	_, err := (typescriptPlugin{}).DecodeOptions(map[string]interface{}{
+		"scalars": map[string]interface{}{"Upload": map[string]interface{}{"input": "File"}},
+	})
+	if err == nil {
+		t.Errorf("scalar without an output type was accepted")
+	}
+}
+
+// TestConvertEnum tests the options that change how enums are written
+func TestConvertEnum(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name:  "schema.graphql",
+		Input: "enum Role { ADMIN\nread_only }\ntype Query { role: Role }",
+	})
+
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected string
+		imports  string
+	}{
+		{
+			name:     "Enum",
+			expected: "export enum Role {\n\tADMIN = 'ADMIN',\n\tREAD_ONLY = 'read_only'\n}\n",
+		},
+		{
+			name:     "NamingConvention",
+			config:   map[string]interface{}{"namingConvention": "change-case-all#pascalCase"},
+			expected: "export enum Role {\n\tAdmin = 'ADMIN',\n\tReadOnly = 'read_only'\n}\n",
+		},
+		{
+			name:     "NamingConventionObject",
+			config:   map[string]interface{}{"namingConvention": map[string]interface{}{"enumValues": "keep"}},
+			expected: "export enum Role {\n\tADMIN = 'ADMIN',\n\tread_only = 'read_only'\n}\n",
+		},
+		{
+			name:     "NamingConventionNotIdentifier",
+			config:   map[string]interface{}{"namingConvention": map[string]interface{}{"enumValues": "change-case-all#paramCase"}},
+			expected: "export enum Role {\n\tadmin = 'ADMIN',\n\t'read-only' = 'read_only'\n}\n",
+		},
+		{
+			name:     "EnumsAsConstNotIdentifier",
+			config:   map[string]interface{}{"enumsAsConst": true, "namingConvention": map[string]interface{}{"enumValues": "paramCase"}},
+			expected: "export const Role = {\n\tadmin: 'ADMIN',\n\t'read-only': 'read_only'\n} as const;\n",
+		},
+		{
+			name:     "EnumsAsTypes",
+			config:   map[string]interface{}{"enumsAsTypes": true, "futureProofEnums": true},
+			expected: "export type Role =\n\t| 'ADMIN'\n\t| 'read_only'\n\t| '%future added value';\n",
+		},
+		{
+			name:   "EnumsAsConst",
+			config: map[string]interface{}{"enumsAsConst": true},
+			expected: "export const Role = {\n\tADMIN: 'ADMIN',\n\tREAD_ONLY: 'read_only'\n} as const;\n" +
+				"export type Role = typeof Role[keyof typeof Role];\n",
+		},
+		{
+			name:     "EnumValues",
+			config:   map[string]interface{}{"enumValues": map[string]interface{}{"Role": map[string]interface{}{"ADMIN": "admin"}}},
+			expected: "export enum Role {\n\tADMIN = 'admin',\n\tREAD_ONLY = 'read_only'\n}\n",
+		},
+		{
+			name:     "EnumValuesImport",
+			config:   map[string]interface{}{"enumValues": map[string]interface{}{"Role": "./enums#UserRole"}},
+			expected: "export { Role };\n",
+			imports:  "import { UserRole as Role } from './enums';\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := (typescriptPlugin{}).DecodeOptions(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			output := strings.Builder{}
+			ConvertSchema(schema, &output, true, nil, options.(TypescriptOptions))
+
+			if !strings.Contains(output.String(), tt.expected) {
+				t.Errorf("ConvertSchema() = %s, expected it to contain %s", output.String(), tt.expected)
+			}
+			if !strings.HasPrefix(output.String(), tt.imports) {
+				t.Errorf("ConvertSchema() does not start with the imports %s", tt.imports)
+			}
+		})
+	}
+
+	if _, err := (typescriptPlugin{}).DecodeOptions(map[string]interface{}{"namingConvention": "titleCase"}); err == nil {
+		t.Errorf("unknown naming convention was accepted")
+	}
+}
@@ -0,0 +1,246 @@
+package internal
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+/*
+presets are the presets a generates entry may use
+*/
+var presets = []string{"client"}
+
+/*
+ExpandPreset returns the files a generates entry writes, keyed by destination. An entry without a preset writes its
+own destination, a preset writes several files, each generated like an entry of its own.
+*/
+func (c Config) ExpandPreset(destination string, generates Generates) (map[string]Generates, error) {
+	switch generates.Preset {
+	case "":
+		return map[string]Generates{destination: generates}, nil
+	case "client":
+		return c.clientPreset(destination, generates)
+	}
+
+	return nil, fmt.Errorf("unknown preset %q, available presets are %s", generates.Preset, strings.Join(presets, ", "))
+}
+
+/*
+clientPresetConfig is the presetConfig of the client preset
+*/
+type clientPresetConfig struct {
+	// fragmentMasking writes fragment-masking.ts and masks the fields of spread fragments, on by default
+	fragmentMasking bool
+	// unmaskFunctionName is the name of the function that unmasks fragments, useFragment by default
+	unmaskFunctionName string
+	// gqlTagName is the name of the function documents are written with, graphql by default
+	gqlTagName string
+}
+
+func getClientPresetConfig(presetConfig map[string]interface{}) (clientPresetConfig, error) {
+	config := clientPresetConfig{
+		fragmentMasking:    true,
+		unmaskFunctionName: "useFragment",
+		gqlTagName:         "graphql",
+	}
+
+	if fragmentMaskingValue, ok := presetConfig["fragmentMasking"]; ok {
+		switch v := fragmentMaskingValue.(type) {
+		case bool:
+			config.fragmentMasking = v
+		case map[string]interface{}:
+			if unmaskFunctionName, ok := v["unmaskFunctionName"]; ok {
+				name, ok := unmaskFunctionName.(string)
+				if !ok {
+					return config, fmt.Errorf("error parsing 'fragmentMasking': unmaskFunctionName is not a string")
+				}
+				config.unmaskFunctionName = name
+			}
+		default:
+			return config, fmt.Errorf("error parsing 'fragmentMasking': value is not a boolean or object")
+		}
+	}
+
+	if gqlTagNameValue, ok := presetConfig["gqlTagName"]; ok {
+		gqlTagName, ok := gqlTagNameValue.(string)
+		if !ok {
+			return config, fmt.Errorf("error parsing 'gqlTagName': value is not a string")
+		}
+		config.gqlTagName = gqlTagName
+	}
+
+	return config, nil
+}
+
+/*
+clientPreset writes the files of @graphql-codegen/client-preset to a directory: graphql.ts with the schema types and
+typed documents, gql.ts with the function that returns them, fragment-masking.ts and index.ts. The plugins of the
+entry are added to graphql.ts.
+*/
+func (c Config) clientPreset(destination string, generates Generates) (map[string]Generates, error) {
+	if !strings.HasSuffix(destination, "/") {
+		return nil, fmt.Errorf("target output of the client preset should be a directory ending with a slash, like \"src/gql/\"")
+	}
+
+	presetConfig, err := getClientPresetConfig(generates.PresetConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	file := func(plugins []string, config map[string]interface{}, pluginConfig map[string]map[string]interface{}) Generates {
+		return Generates{
+			Plugins:      plugins,
+			PluginConfig: pluginConfig,
+			Overwrite:    generates.Overwrite,
+			Sort:         generates.Sort,
+			Header:       generates.Header,
+			Config:       config,
+		}
+	}
+
+	// the files besides graphql.ts are written with the plugins only presets can use
+	presetFile := func(plugins []string, config map[string]interface{}, pluginConfig map[string]map[string]interface{}) Generates {
+		generates := file(plugins, config, pluginConfig)
+		generates.presetFile = true
+		return generates
+	}
+
+	// like upstream, fragments are masked unless inlineFragmentTypes is configured
+	graphqlConfig := maps.Clone(generates.Config)
+	if presetConfig.fragmentMasking && c.PluginConfig(generates, "typescript-operations")["inlineFragmentTypes"] == nil {
+		if graphqlConfig == nil {
+			graphqlConfig = make(map[string]interface{})
+		}
+		graphqlConfig["inlineFragmentTypes"] = "mask"
+	}
+
+	// plugins of the entry the preset already runs are not run twice
+	graphqlPlugins := []string{"typescript", "typescript-operations", "typed-document-node"}
+	for _, plugin := range generates.Plugins {
+		if !slices.Contains(graphqlPlugins, plugin) {
+			graphqlPlugins = append(graphqlPlugins, plugin)
+		}
+	}
+
+	files := map[string]Generates{
+		destination + "graphql.ts": file(
+			graphqlPlugins,
+			graphqlConfig,
+			generates.PluginConfig,
+		),
+		destination + "gql.ts": presetFile(
+			[]string{"add", "gql-tag-operations"},
+			generates.Config,
+			map[string]map[string]interface{}{
+				"add":                {"content": "/* eslint-disable */"},
+				"gql-tag-operations": {"gqlTagName": presetConfig.gqlTagName},
+			},
+		),
+	}
+
+	index := `export * from "./gql";`
+	if presetConfig.fragmentMasking {
+		files[destination+"fragment-masking.ts"] = presetFile([]string{"add"}, nil, map[string]map[string]interface{}{
+			"add": {"content": strings.ReplaceAll(fragmentMasking, "useFragment", presetConfig.unmaskFunctionName)},
+		})
+		index = "export * from \"./fragment-masking\";\n" + index
+	}
+
+	files[destination+"index.ts"] = presetFile([]string{"add"}, nil, map[string]map[string]interface{}{
+		"add": {"content": index},
+	})
+
+	return files, nil
+}
+
+/*
+fragmentMasking is the content of fragment-masking.ts, like upstream writes it
+*/
+const fragmentMasking = `/* eslint-disable */
+import { ResultOf, DocumentTypeDecoration, TypedDocumentNode } from '@graphql-typed-document-node/core';
+import { FragmentDefinitionNode } from 'graphql';
+import { Incremental } from './graphql';
+
+export type FragmentType<TDocumentType extends DocumentTypeDecoration<any, any>> = TDocumentType extends DocumentTypeDecoration<
+	infer TType,
+	any
+>
+	? [TType] extends [{ ' $fragmentName'?: infer TKey }]
+		? TKey extends string
+			? { ' $fragmentRefs'?: { [key in TKey]: TType } }
+			: never
+		: never
+	: never;
+
+// return non-nullable if ` + "`fragmentType`" + ` is non-nullable
+export function useFragment<TType>(
+	_documentNode: DocumentTypeDecoration<TType, any>,
+	fragmentType: FragmentType<DocumentTypeDecoration<TType, any>>
+): TType;
+// return nullable if ` + "`fragmentType`" + ` is undefined
+export function useFragment<TType>(
+	_documentNode: DocumentTypeDecoration<TType, any>,
+	fragmentType: FragmentType<DocumentTypeDecoration<TType, any>> | undefined
+): TType | undefined;
+// return nullable if ` + "`fragmentType`" + ` is nullable
+export function useFragment<TType>(
+	_documentNode: DocumentTypeDecoration<TType, any>,
+	fragmentType: FragmentType<DocumentTypeDecoration<TType, any>> | null
+): TType | null;
+// return nullable if ` + "`fragmentType`" + ` is nullable or undefined
+export function useFragment<TType>(
+	_documentNode: DocumentTypeDecoration<TType, any>,
+	fragmentType: FragmentType<DocumentTypeDecoration<TType, any>> | null | undefined
+): TType | null | undefined;
+// return array of non-nullable if ` + "`fragmentType`" + ` is array of non-nullable
+export function useFragment<TType>(
+	_documentNode: DocumentTypeDecoration<TType, any>,
+	fragmentType: Array<FragmentType<DocumentTypeDecoration<TType, any>>>
+): Array<TType>;
+// return array of nullable if ` + "`fragmentType`" + ` is array of nullable
+export function useFragment<TType>(
+	_documentNode: DocumentTypeDecoration<TType, any>,
+	fragmentType: Array<FragmentType<DocumentTypeDecoration<TType, any>>> | null | undefined
+): Array<TType> | null | undefined;
+// return readonly array of non-nullable if ` + "`fragmentType`" + ` is array of non-nullable
+export function useFragment<TType>(
+	_documentNode: DocumentTypeDecoration<TType, any>,
+	fragmentType: ReadonlyArray<FragmentType<DocumentTypeDecoration<TType, any>>>
+): ReadonlyArray<TType>;
+// return readonly array of nullable if ` + "`fragmentType`" + ` is array of nullable
+export function useFragment<TType>(
+	_documentNode: DocumentTypeDecoration<TType, any>,
+	fragmentType: ReadonlyArray<FragmentType<DocumentTypeDecoration<TType, any>>> | null | undefined
+): ReadonlyArray<TType> | null | undefined;
+export function useFragment<TType>(
+	_documentNode: DocumentTypeDecoration<TType, any>,
+	fragmentType: FragmentType<DocumentTypeDecoration<TType, any>> | Array<FragmentType<DocumentTypeDecoration<TType, any>>> | ReadonlyArray<FragmentType<DocumentTypeDecoration<TType, any>>> | null | undefined
+): TType | Array<TType> | ReadonlyArray<TType> | null | undefined {
+	return fragmentType as any;
+}
+
+export function makeFragmentData<
+	F extends DocumentTypeDecoration<any, any>,
+	FT extends ResultOf<F>
+>(data: FT, _fragment: F): FragmentType<F> {
+	return data as FragmentType<F>;
+}
+
+export function isFragmentReady<TQuery, TFrag>(
+	queryNode: DocumentTypeDecoration<TQuery, any>,
+	fragmentNode: TypedDocumentNode<TFrag>,
+	data: FragmentType<TypedDocumentNode<Incremental<TFrag>, any>> | null | undefined
+): data is FragmentType<typeof fragmentNode> {
+	const deferredFields = (queryNode as { __meta__?: { deferredFields: Record<string, (keyof TFrag)[]> } }).__meta__
+		?.deferredFields;
+
+	if (!deferredFields) return true;
+
+	const fragDef = fragmentNode.definitions[0] as FragmentDefinitionNode | undefined;
+	const fragName = fragDef?.name?.value;
+
+	const fields = (fragName && deferredFields[fragName]) || [];
+	return fields.length > 0 && fields.every(field => data && field in data);
+}`
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// TestExpandPreset tests the files and config of the client preset, and entries without a preset
+func TestExpandPreset(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          Config
+		generates       Generates
+		files           []string
+		graphqlPlugins  []string
+		inlineFragments interface{}
+		wantErr         bool
+	}{
+		{
+			name:      "NoPreset",
+			generates: Generates{Plugins: []string{"typescript"}},
+			files:     []string{"src/gql/"},
+		},
+		{
+			name:            "Client",
+			generates:       Generates{Preset: "client", Plugins: []string{"typescript", "introspection"}},
+			files:           []string{"src/gql/fragment-masking.ts", "src/gql/gql.ts", "src/gql/graphql.ts", "src/gql/index.ts"},
+			graphqlPlugins:  []string{"typescript", "typescript-operations", "typed-document-node", "introspection"},
+			inlineFragments: "mask",
+		},
+		{
+			name:            "WithoutFragmentMasking",
+			generates:       Generates{Preset: "client", PresetConfig: map[string]interface{}{"fragmentMasking": false}},
+			files:           []string{"src/gql/gql.ts", "src/gql/graphql.ts", "src/gql/index.ts"},
+			graphqlPlugins:  []string{"typescript", "typescript-operations", "typed-document-node"},
+			inlineFragments: nil,
+		},
+		{
+			name:            "ConfiguredInlineFragmentTypes",
+			config:          Config{Config: map[string]interface{}{"inlineFragmentTypes": "inline"}},
+			generates:       Generates{Preset: "client"},
+			files:           []string{"src/gql/fragment-masking.ts", "src/gql/gql.ts", "src/gql/graphql.ts", "src/gql/index.ts"},
+			graphqlPlugins:  []string{"typescript", "typescript-operations", "typed-document-node"},
+			inlineFragments: nil,
+		},
+		{
+			name: "ConfiguredInlineFragmentTypesOfPlugin",
+			generates: Generates{
+				Preset:       "client",
+				PluginConfig: map[string]map[string]interface{}{"typescript-operations": {"inlineFragmentTypes": "combine"}},
+			},
+			files:           []string{"src/gql/fragment-masking.ts", "src/gql/gql.ts", "src/gql/graphql.ts", "src/gql/index.ts"},
+			graphqlPlugins:  []string{"typescript", "typescript-operations", "typed-document-node"},
+			inlineFragments: nil,
+		},
+		{
+			name:      "InvalidPresetConfig",
+			generates: Generates{Preset: "client", PresetConfig: map[string]interface{}{"gqlTagName": 1}},
+			wantErr:   true,
+		},
+		{
+			name:      "UnknownPreset",
+			generates: Generates{Preset: "near-operation-file"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := tt.config.ExpandPreset("src/gql/", tt.generates)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandPreset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if destinations := slices.Sorted(maps.Keys(files)); !slices.Equal(destinations, tt.files) {
+				t.Errorf("ExpandPreset() files = %v, expected %v", destinations, tt.files)
+			}
+
+			graphql, ok := files["src/gql/graphql.ts"]
+			if !ok {
+				return
+			}
+			if !slices.Equal(graphql.Plugins, tt.graphqlPlugins) {
+				t.Errorf("ExpandPreset() graphql.ts plugins = %v, expected %v", graphql.Plugins, tt.graphqlPlugins)
+			}
+			if inlineFragments := graphql.Config["inlineFragmentTypes"]; inlineFragments != tt.inlineFragments {
+				t.Errorf("ExpandPreset() graphql.ts inlineFragmentTypes = %v, expected %v", inlineFragments, tt.inlineFragments)
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package internal
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"github.com/simse/faster-graphql-codegen/internal/plugins"
 	"github.com/vektah/gqlparser/v2/ast"
 	"io/fs"
-	"log/slog"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
@@ -14,10 +17,11 @@ import (
 )
 
 type Project struct {
-	RootDir    string
-	ConfigFile string
-	Schemas    []string
-	config     Config
+	RootDir       string
+	ConfigFile    string
+	Schemas       []string
+	SchemaHeaders map[string]map[string]string
+	config        Config
 }
 
 type ProjectLoadError struct {
@@ -58,14 +62,12 @@ func FindProjects(rootDir string, walkDir func(string, fs.WalkDirFunc) error) (F
 			}
 
 			// prime project
-			config, configLoadError := project.GetConfig()
-			if configLoadError != nil {
+			if configLoadError := project.Refresh(); configLoadError != nil {
 				result.ProjectLoadErrors = append(result.ProjectLoadErrors, ProjectLoadError{
 					Error:    configLoadError,
 					FilePath: absolutePath,
 				})
 			} else {
-				project.Schemas = config.Schemas
 				result.Projects = append(result.Projects, project)
 			}
 		}
@@ -80,46 +82,231 @@ func FindProjects(rootDir string, walkDir func(string, fs.WalkDirFunc) error) (F
 }
 
 /*
-SchemaKey generates a string get is unique to a combination of schema documents
+Refresh reads the config file of the project again and resolves its schemas
+*/
+func (p *Project) Refresh() error {
+	config, err := p.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	schemas, err := ExpandSchemas(p.RootDir, config.Schemas)
+	if err != nil {
+		return err
+	}
+
+	p.Schemas = schemas
+	p.SchemaHeaders = config.SchemaHeaders
+
+	return nil
+}
+
+/*
+WatchedFiles returns the local files the output of the project depends on: its config file, schemas and documents.
+Generated files are left out, so writing them never counts as a change.
+*/
+func (p *Project) WatchedFiles() ([]string, error) {
+	config, err := p.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := ExpandGlobs(p.RootDir, config.Documents)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{filepath.Join(p.RootDir, p.ConfigFile)}
+	for _, file := range slices.Concat(p.Schemas, documents) {
+		if !IsURL(file) {
+			files = append(files, file)
+		}
+	}
+
+	destinations := p.outputs(config)
+	files = slices.DeleteFunc(files, func(file string) bool {
+		return slices.Contains(destinations, file)
+	})
+
+	slices.Sort(files)
+
+	return slices.Compact(files), nil
+}
+
+/*
+MatchFile checks if a file, which may not have existed when the project was loaded, is matched by the schema or
+document patterns of the project. Generated files are never matched.
+*/
+func (p *Project) MatchFile(file string) (schema bool, document bool, err error) {
+	config, err := p.GetConfig()
+	if err != nil {
+		return false, false, err
+	}
+
+	if slices.Contains(p.outputs(config), file) {
+		return false, false, nil
+	}
+
+	return MatchGlobs(p.RootDir, config.Schemas, file), MatchGlobs(p.RootDir, config.Documents, file), nil
+}
+
+/*
+GlobDirs returns the directories the schema and document glob patterns of the project search for files
+*/
+func (p *Project) GlobDirs() ([]string, error) {
+	config, err := p.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return GlobDirs(p.RootDir, slices.Concat(config.Schemas, config.Documents)), nil
+}
+
+/*
+outputs returns the files the generates entries of the config write to
+*/
+func (p *Project) outputs(config Config) []string {
+	var destinations []string
+	for destination, generates := range config.Generates {
+		// an entry with an unknown preset generates nothing
+		outputs, _ := config.ExpandPreset(destination, generates)
+		for output := range outputs {
+			destinations = append(destinations, filepath.Join(p.RootDir, output))
+		}
+	}
+
+	return destinations
+}
+
+/*
+ExpandSchemas resolves the schema pointers of a config into the files they match
+*/
+func ExpandSchemas(rootDir string, schemas []string) ([]string, error) {
+	expandedSchemas, err := ExpandGlobs(rootDir, schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(expandedSchemas) == 0 {
+		return nil, errors.New("schema did not match any files: " + strings.Join(schemas, ", "))
+	}
+
+	return expandedSchemas, nil
+}
+
+/*
+SchemaKey generates a string get is unique to a combination of schema documents. A URL is keyed with the headers it
+is requested with, since other headers may return another schema.
 */
 func (p *Project) SchemaKey() string {
-	projectConfig, _ := p.GetConfig()
+	keys := make([]string, 0, len(p.Schemas))
+	for _, schema := range p.Schemas {
+		if headers := p.SchemaHeaders[schema]; IsURL(schema) && len(headers) > 0 {
+			schema += "#" + hashHeaders(headers)
+		}
+		keys = append(keys, schema)
+	}
+	slices.Sort(keys)
+
+	return strings.Join(keys, ",")
+}
 
-	sortedSchemas := slices.Clone(projectConfig.Schemas)
-	slices.Sort(sortedSchemas)
+/*
+hashHeaders hashes request headers, so secrets such as tokens do not end up in schema keys
+*/
+func hashHeaders(headers map[string]string) string {
+	hash := sha256.New()
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		hash.Write([]byte(name))
+		hash.Write([]byte{0})
+		hash.Write([]byte(headers[name]))
+		hash.Write([]byte{0})
+	}
 
-	return strings.Join(sortedSchemas, ",")
+	return hex.EncodeToString(hash.Sum(nil))[:16]
 }
 
 type ExecutionContext struct {
 	Projects      []Project
 	LoadedSchemas map[string]*ast.Schema
+	// SchemaHashes holds the HashSourceContents of every loaded schema, by schema key
+	SchemaHashes map[string]string
+	// SchemaCache is used when loading schemas, nil disables caching
+	SchemaCache *SchemaCache
+	// Check compares generated output to the files on disk instead of writing it
+	Check bool
+	// Diff creates a diff for every out of date file in check mode
+	Diff bool
+
+	mu sync.Mutex
 }
 
 func (e *ExecutionContext) SetProjects(projects []Project) {
 	e.Projects = projects
 }
 
-func (e *ExecutionContext) AddLoadedSchema(key string, schema *ast.Schema) {
+func (e *ExecutionContext) AddLoadedSchema(key string, schema *ast.Schema, hash string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	if e.LoadedSchemas == nil {
 		e.LoadedSchemas = make(map[string]*ast.Schema)
+		e.SchemaHashes = make(map[string]string)
 	}
 
 	e.LoadedSchemas[key] = schema
+	e.SchemaHashes[key] = hash
+}
+
+func (e *ExecutionContext) RemoveLoadedSchema(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.LoadedSchemas, key)
+	delete(e.SchemaHashes, key)
 }
 
 func (e *ExecutionContext) GetSchema(key string) *ast.Schema {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	return e.LoadedSchemas[key]
 }
 
+func (e *ExecutionContext) GetSchemaHash(key string) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.SchemaHashes[key]
+}
+
 /*
 LoadSchemas will find every project with a unique list of schemas and load those to cache.
+It returns the number of schemas loaded, and an error for every schema that failed to load.
+*/
+func (e *ExecutionContext) LoadSchemas() (int, []*TaskError) {
+	return e.loadSchemas(e.Projects)
+}
+
+/*
+ReloadSchemas loads the schemas with the given keys again, other schemas are kept as they are
 */
-func (e *ExecutionContext) LoadSchemas() int {
+func (e *ExecutionContext) ReloadSchemas(keys []string) (int, []*TaskError) {
+	var projects []Project
+	for _, project := range e.Projects {
+		if slices.Contains(keys, project.SchemaKey()) {
+			projects = append(projects, project)
+		}
+	}
+
+	return e.loadSchemas(projects)
+}
+
+func (e *ExecutionContext) loadSchemas(projects []Project) (int, []*TaskError) {
 	// find unique schemas
 	var uniqueSchemas []string
 	var projectsToLoad []Project
-	for _, project := range e.Projects {
+	for _, project := range projects {
 		schemaKey := project.SchemaKey()
 
 		if !slices.Contains(uniqueSchemas, schemaKey) {
@@ -130,86 +317,213 @@ func (e *ExecutionContext) LoadSchemas() int {
 
 	// load each schema in parrallel
 	var wg sync.WaitGroup
+	errorCollector := taskErrorCollector{}
 	for _, project := range projectsToLoad {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
 
-			var schemas []string
-			for _, schema := range project.Schemas {
-				schemas = append(schemas, path.Join(project.RootDir, schema))
+			sources, err := ReadSchemaSources(SchemaLoadOptions{
+				Headers: project.SchemaHeaders,
+			}, project.Schemas...)
+
+			var loadedSchema *ast.Schema
+			if err == nil {
+				loadedSchema, err = LoadSchemaSourcesWithCache(e.SchemaCache, sources...)
 			}
 
-			loadedSchema, err := LoadSchema(schemas...)
 			if err != nil {
-				panic(err)
+				// a schema that fails to reload must not be generated from its previous version
+				e.RemoveLoadedSchema(project.SchemaKey())
+
+				errorCollector.Add(&TaskError{
+					ProjectRoot: project.RootDir,
+					ConfigFile:  project.ConfigFile,
+					Err:         err,
+				})
+				return
 			}
 
-			e.AddLoadedSchema(project.SchemaKey(), loadedSchema)
+			e.AddLoadedSchema(project.SchemaKey(), loadedSchema, HashSourceContents(sources))
 		}()
 	}
 
 	wg.Wait()
 
-	return len(uniqueSchemas)
+	taskErrors := errorCollector.Errors()
+
+	return len(uniqueSchemas) - len(taskErrors), taskErrors
+}
+
+/*
+Execute runs every generation task of every project whose schema loaded. A failing task does not stop the others,
+its error is returned in the result instead.
+*/
+func (e *ExecutionContext) Execute() ExecuteResult {
+	return e.ExecuteProjects(e.Projects)
 }
 
-func (e *ExecutionContext) Execute() {
+/*
+ExecuteProjects runs the generation tasks of the given projects only, like Execute does for every project
+*/
+func (e *ExecutionContext) ExecuteProjects(projects []Project) ExecuteResult {
 	var wg sync.WaitGroup
+	var destinationsMu sync.Mutex
+	var destinations []DestinationResult
+	var warnings []*TaskError
+	errorCollector := taskErrorCollector{}
 
-	for _, project := range e.Projects {
-		// get schema from cache
+	for _, project := range projects {
+		// get schema from cache, a schema that failed to load has already been reported
 		schema := e.GetSchema(project.SchemaKey())
+		if schema == nil {
+			continue
+		}
 
 		// execute all generation tasks
-		config, _ := project.GetConfig()
-		for destination, destinationConfig := range config.Generates {
-			wg.Add(1)
-
-			go func() {
-				defer wg.Done()
-
-				// ensure output dir exists
-				destinationFile := path.Join(project.RootDir, destination)
-				dirCreationErr := EnsureDir(destinationFile)
-				if dirCreationErr != nil {
-					panic(dirCreationErr)
-				}
-
-				// create output string in memory
-				output := strings.Builder{}
-
-				e.ExecuteDestinationTasks(destinationConfig, &output, schema, project)
-
-				// create output file
-				outputFile, openErr := os.Create(destinationFile)
-				if openErr != nil {
-					panic(openErr)
-				}
-
-				// write output file
-				_, writeErr := outputFile.WriteString(output.String())
-				if writeErr != nil {
-					panic(writeErr)
-				}
-
-				// close output files
-				err := outputFile.Close()
-				if err != nil {
-					panic(err)
-				}
-			}()
+		config, configErr := project.GetConfig()
+		if configErr != nil {
+			errorCollector.Add(&TaskError{
+				ProjectRoot: project.RootDir,
+				ConfigFile:  project.ConfigFile,
+				Err:         configErr,
+			})
+			continue
+		}
+
+		// documents are shared by every destination, so they are validated once before any of them runs
+		documents, documentsErr := LoadDocuments(project.RootDir, config.Documents)
+		if documentsErr != nil {
+			errorCollector.Add(&TaskError{
+				ProjectRoot: project.RootDir,
+				ConfigFile:  project.ConfigFile,
+				Documents:   true,
+				Err:         documentsErr,
+			})
+			continue
+		}
+
+		validationErrors := ValidateDocuments(project, schema, documents)
+		if config.IgnoreValidationErrors {
+			warnings = append(warnings, validationErrors...)
+		} else if len(validationErrors) > 0 {
+			for _, validationError := range validationErrors {
+				errorCollector.Add(validationError)
+			}
+			continue
+		}
+
+		for entryDestination, entryConfig := range config.Generates {
+			// a preset writes several files, each generated like an entry of its own
+			outputs, err := config.ExpandPreset(entryDestination, entryConfig)
+			if err != nil {
+				errorCollector.Add(&TaskError{
+					ProjectRoot: project.RootDir,
+					ConfigFile:  project.ConfigFile,
+					Destination: entryDestination,
+					Err:         err,
+				})
+				continue
+			}
+
+			for destination, destinationConfig := range outputs {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					status, diff, err := e.executeDestination(project, destination, destinationConfig, schema, documents)
+					if err != nil {
+						taskError := &TaskError{
+							ProjectRoot: project.RootDir,
+							ConfigFile:  project.ConfigFile,
+							Destination: destination,
+							Err:         err,
+						}
+
+						var pluginErr *PluginError
+						if errors.As(err, &pluginErr) {
+							taskError.Plugin = pluginErr.Plugin
+							taskError.Err = pluginErr.Err
+						}
+
+						errorCollector.Add(taskError)
+						return
+					}
+
+					destinationsMu.Lock()
+					defer destinationsMu.Unlock()
+
+					destinations = append(destinations, DestinationResult{
+						ProjectRoot: project.RootDir,
+						ConfigFile:  project.ConfigFile,
+						Destination: destination,
+						Status:      status,
+						Diff:        diff,
+					})
+				}()
+			}
 		}
 	}
 
 	wg.Wait()
+
+	slices.SortFunc(destinations, func(a, b DestinationResult) int {
+		return strings.Compare(path.Join(a.ProjectRoot, a.Destination), path.Join(b.ProjectRoot, b.Destination))
+	})
+
+	return ExecuteResult{
+		Destinations: destinations,
+		Errors:       errorCollector.Errors(),
+		Warnings:     warnings,
+	}
+}
+
+func (e *ExecutionContext) executeDestination(
+	project Project,
+	destination string,
+	destinationConfig Generates,
+	schema *ast.Schema,
+	documents []*plugins.Document,
+) (DestinationStatus, string, error) {
+	// create output string in memory
+	output := strings.Builder{}
+
+	config, err := project.GetConfig()
+	if err != nil {
+		return "", "", err
+	}
+
+	destinationFile := path.Join(project.RootDir, destination)
+	if !config.ShouldOverwrite(destinationConfig) {
+		if _, err := os.Stat(destinationFile); err == nil {
+			return DestinationSkipped, "", nil
+		}
+	}
+
+	inputHash := config.InputHash(project.RootDir, e.GetSchemaHash(project.SchemaKey()), documents, destinationConfig)
+	header := config.HeaderFor(destinationConfig).Render(inputHash)
+	output.WriteString(HeaderComment(destination, header))
+
+	if err := e.ExecuteDestinationTasks(destination, destinationConfig, &output, schema, documents, project); err != nil {
+		return "", "", err
+	}
+
+	if e.Check {
+		return checkOutput(destinationFile, destination, []byte(output.String()), e.Diff)
+	}
+
+	status, err := writeOutput(destinationFile, []byte(output.String()))
+	return status, "", err
 }
 
 func (e *ExecutionContext) ExecuteDestinationTasks(
+	destination string,
 	destinationConfig Generates,
 	output *strings.Builder,
 	schema *ast.Schema,
+	documents []*plugins.Document,
 	project Project,
 ) error {
 	projectConfig, err := project.GetConfig()
@@ -217,26 +531,40 @@ func (e *ExecutionContext) ExecuteDestinationTasks(
 		return err
 	}
 
-	task := plugins.PluginTask{
-		Schema: schema,
-		Output: output,
-		Config: projectConfig,
+	lookup := plugins.Lookup
+	if destinationConfig.presetFile {
+		lookup = plugins.LookupPresetPlugin
 	}
 
-	// execute plugins
-	for _, plugin := range destinationConfig.Plugins {
-		if pluginErr := plugins.VerifyPlugin(plugin, destinationConfig); pluginErr != nil {
-			slog.Error("unknown plugin", "plugin", plugin)
+	// every plugin is checked before any of them runs
+	var destinationPlugins []plugins.Plugin
+	var tasks []plugins.PluginTask
+	for _, pluginName := range destinationConfig.Plugins {
+		var options interface{}
+		plugin, err := lookup(pluginName)
+		if err == nil {
+			err = plugins.ValidateDestination(plugin, destination)
 		}
-
-		// slog.Info(plugin)
-
-		if plugin == "typescript" {
-			task.Typescript()
+		if err == nil {
+			options, err = plugin.DecodeOptions(projectConfig.PluginConfig(destinationConfig, pluginName))
+		}
+		if err != nil {
+			return &PluginError{Plugin: pluginName, Err: err}
 		}
 
-		if plugin == "introspection" {
-			task.Introspect()
+		destinationPlugins = append(destinationPlugins, plugin)
+		tasks = append(tasks, plugins.PluginTask{
+			Schema:      schema,
+			Options:     options,
+			Documents:   documents,
+			Sort:        projectConfig.ShouldSort(destinationConfig),
+			SchemaFiles: project.Schemas,
+		})
+	}
+
+	for i, plugin := range destinationPlugins {
+		if err := plugin.Generate(tasks[i], output); err != nil {
+			return &PluginError{Plugin: plugin.Name(), Err: err}
 		}
 	}
 
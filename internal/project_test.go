@@ -0,0 +1,506 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func findTestProjects(t *testing.T, dir string) []Project {
+	t.Helper()
+
+	result, err := FindProjects(dir, filepath.WalkDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ProjectLoadErrors) > 0 {
+		t.Fatal(result.ProjectLoadErrors[0].Error)
+	}
+
+	return result.Projects
+}
+
+// TestExecuteCollectsErrors tests that failing tasks are reported without stopping other tasks
+func TestExecuteCollectsErrors(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"broken/schema.graphql": "type Query { hello: Unknown }",
+		"broken/codegen.yml":    "schema: schema.graphql\ngenerates:\n  out.ts:\n    plugins: [typescript]\n",
+		"valid/schema.graphql":  "type Query { hello: String }",
+		"valid/codegen.yml":     "schema: schema.graphql\ngenerates:\n  out.ts:\n    plugins: [typescript]\n  bad.ts:\n    plugins: [unknown]\n",
+	})
+
+	e := ExecutionContext{}
+	e.SetProjects(findTestProjects(t, dir))
+
+	schemasLoaded, schemaErrors := e.LoadSchemas()
+	if schemasLoaded != 1 || len(schemaErrors) != 1 {
+		t.Fatalf("LoadSchemas() = %d, %v, expected 1 schema and 1 error", schemasLoaded, schemaErrors)
+	}
+
+	if schemaErrors[0].ProjectRoot != filepath.Join(dir, "broken") || schemaErrors[0].Destination != "" {
+		t.Errorf("LoadSchemas() error = %+v, expected it to belong to the broken project", schemaErrors[0])
+	}
+
+	result := e.Execute()
+	taskErrors := result.Errors
+	if len(taskErrors) != 1 {
+		t.Fatalf("Execute() = %v, expected 1 error", taskErrors)
+	}
+
+	if taskErrors[0].Destination != "bad.ts" || taskErrors[0].Plugin != "unknown" || taskErrors[0].ConfigFile != "codegen.yml" {
+		t.Errorf("Execute() error = %+v, expected it to belong to bad.ts", taskErrors[0])
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "valid", "out.ts")); err != nil {
+		t.Errorf("Execute() did not write valid/out.ts: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "valid", "bad.ts")); !os.IsNotExist(err) {
+		t.Errorf("Execute() wrote the output of a failed task")
+	}
+}
+
+// TestExecuteSkipsUnchangedFiles tests that files are only written when their content changes
+func TestExecuteSkipsUnchangedFiles(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"schema.graphql": "type Query { hello: String }",
+		"codegen.yml":    "schema: schema.graphql\ngenerates:\n  out.ts:\n    plugins: [typescript]\n",
+	})
+	outputFile := filepath.Join(dir, "out.ts")
+
+	execute := func() DestinationStatus {
+		e := ExecutionContext{}
+		e.SetProjects(findTestProjects(t, dir))
+		e.LoadSchemas()
+
+		result := e.Execute()
+		if len(result.Errors) > 0 || len(result.Destinations) != 1 {
+			t.Fatalf("Execute() = %+v, expected 1 destination", result)
+		}
+
+		return result.Destinations[0].Status
+	}
+
+	if status := execute(); status != DestinationCreated {
+		t.Errorf("first run status = %s, expected %s", status, DestinationCreated)
+	}
+
+	// backdate the output, so a rewrite would be visible
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(outputFile, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := execute(); status != DestinationUnchanged {
+		t.Errorf("second run status = %s, expected %s", status, DestinationUnchanged)
+	}
+
+	if info, _ := os.Stat(outputFile); !info.ModTime().Equal(past) {
+		t.Errorf("second run changed the modification time of an unchanged file")
+	}
+
+	if err := os.WriteFile(outputFile, []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := execute(); status != DestinationUpdated {
+		t.Errorf("third run status = %s, expected %s", status, DestinationUpdated)
+	}
+}
+
+// TestExecuteCheck tests that check mode reports out of date files without writing them
+func TestExecuteCheck(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"schema.graphql": "type Query { hello: String }",
+		"codegen.yml":    "schema: schema.graphql\ngenerates:\n  out.ts:\n    plugins: [typescript]\n",
+	})
+	outputFile := filepath.Join(dir, "out.ts")
+
+	check := func() DestinationResult {
+		e := ExecutionContext{Check: true, Diff: true}
+		e.SetProjects(findTestProjects(t, dir))
+		e.LoadSchemas()
+
+		result := e.Execute()
+		if len(result.Errors) > 0 || len(result.Destinations) != 1 {
+			t.Fatalf("Execute() = %+v, expected 1 destination", result)
+		}
+
+		return result.Destinations[0]
+	}
+
+	if result := check(); result.Status != DestinationMissing {
+		t.Errorf("status without output = %s, expected %s", result.Status, DestinationMissing)
+	}
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		t.Fatalf("check mode wrote %s", outputFile)
+	}
+
+	e := ExecutionContext{}
+	e.SetProjects(findTestProjects(t, dir))
+	e.LoadSchemas()
+	e.Execute()
+
+	if result := check(); result.Status != DestinationUnchanged || result.Diff != "" {
+		t.Errorf("status after generating = %s with diff %q, expected %s", result.Status, result.Diff, DestinationUnchanged)
+	}
+
+	if err := os.WriteFile(outputFile, []byte("stale\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := check()
+	if result.Status != DestinationStale {
+		t.Errorf("status after editing = %s, expected %s", result.Status, DestinationStale)
+	}
+	if !strings.Contains(result.Diff, "-stale\n") || !strings.Contains(result.Diff, "+++ b/out.ts") {
+		t.Errorf("diff = %q, expected the edited line to be removed", result.Diff)
+	}
+
+	if content, _ := os.ReadFile(outputFile); string(content) != "stale\n" {
+		t.Errorf("check mode overwrote an out of date file")
+	}
+}
+
+// TestExecuteOverwrite tests that existing files are only replaced when overwrite allows it
+func TestExecuteOverwrite(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"schema.graphql": "type Query { hello: String }",
+		"codegen.yml": "schema: schema.graphql\noverwrite: false\ngenerates:\n" +
+			"  scaffold.ts:\n    plugins: [typescript]\n" +
+			"  types.ts:\n    plugins: [typescript]\n    overwrite: true\n",
+	})
+
+	execute := func() map[string]DestinationStatus {
+		e := ExecutionContext{}
+		e.SetProjects(findTestProjects(t, dir))
+		e.LoadSchemas()
+
+		result := e.Execute()
+		if len(result.Errors) > 0 {
+			t.Fatalf("Execute() errors = %v", result.Errors)
+		}
+
+		statuses := make(map[string]DestinationStatus)
+		for _, destination := range result.Destinations {
+			statuses[destination.Destination] = destination.Status
+		}
+
+		return statuses
+	}
+
+	if statuses := execute(); statuses["scaffold.ts"] != DestinationCreated || statuses["types.ts"] != DestinationCreated {
+		t.Errorf("first run statuses = %v, expected both files to be created", statuses)
+	}
+
+	for _, file := range []string{"scaffold.ts", "types.ts"} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("edited"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if statuses := execute(); statuses["scaffold.ts"] != DestinationSkipped || statuses["types.ts"] != DestinationUpdated {
+		t.Errorf("second run statuses = %v, expected scaffold.ts to be skipped and types.ts updated", statuses)
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(dir, "scaffold.ts")); string(content) != "edited" {
+		t.Errorf("scaffold.ts was overwritten")
+	}
+}
+
+// TestExecuteOperations tests that documents are loaded for operation types and invalid documents fail the project
+func TestExecuteOperations(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"valid/schema.graphql":            "type Query { hello: String }",
+		"valid/queries/hello.graphql":     "query Hello { hello }",
+		"valid/codegen.yml":               "schema: schema.graphql\ndocuments: [queries/*.graphql]\ngenerates:\n  types.ts:\n    plugins: [typescript, typescript-operations]\n",
+		"invalid/schema.graphql":          "type Query { hello: String }",
+		"invalid/queries/goodbye.graphql": "query Goodbye {\n  goodbye\n}",
+		"invalid/codegen.yml":             "schema: schema.graphql\ndocuments: [queries/*.graphql]\ngenerates:\n  types.ts:\n    plugins: [typescript-operations]\n",
+	})
+
+	e := ExecutionContext{}
+	e.SetProjects(findTestProjects(t, dir))
+	e.LoadSchemas()
+
+	result := e.Execute()
+	if len(result.Errors) != 1 || result.Errors[0].ProjectRoot != filepath.Join(dir, "invalid") || !result.Errors[0].Documents {
+		t.Fatalf("Execute() errors = %v, expected a documents error for the invalid project", result.Errors)
+	}
+
+	var diagnostic *Diagnostic
+	if !errors.As(result.Errors[0], &diagnostic) || diagnostic.Line != 2 || !strings.HasSuffix(diagnostic.File, "goodbye.graphql") {
+		t.Errorf("Execute() error = %v, expected it to point at goodbye.graphql:2", result.Errors[0])
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "valid", "types.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "export type HelloQuery = { __typename?: 'Query', hello?: Scalars['String']['output'] | null };") {
+		t.Errorf("types.ts does not contain the HelloQuery type:\n%s", content)
+	}
+}
+
+// TestExecuteSchemaPointerOrder tests that unsorted output follows the order of the schema pointers, not file names
+func TestExecuteSchemaPointerOrder(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"schema/z.graphql": "type Query { user: User }\ntype User { id: ID }",
+		"schema/a.graphql": "type Post { id: ID }",
+		"codegen.yml":      "schema: [schema/z.graphql, schema/*.graphql]\ngenerates:\n  types.ts:\n    sort: false\n    plugins: [typescript]\n",
+	})
+
+	e := ExecutionContext{}
+	e.SetProjects(findTestProjects(t, dir))
+	e.LoadSchemas()
+	if result := e.Execute(); len(result.Errors) > 0 {
+		t.Fatal(result.Errors[0])
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "types.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query, user, post := strings.Index(string(content), "type Query"), strings.Index(string(content), "type User"), strings.Index(string(content), "type Post")
+	if query == -1 || !(query < user && user < post) {
+		t.Errorf("types.ts is not in the order of the schema pointers:\n%s", content)
+	}
+}
+
+// TestExecuteClientPreset tests that the client preset writes its files to the output directory
+func TestExecuteClientPreset(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"schema.graphql": "type Query { user: User }\ntype User { id: ID!, name: String }",
+		"src/User.tsx": "import { graphql } from './gql';\n\nconst UserFields = graphql(`fragment UserFields on User { name }`);\n" +
+			"const GetUser = graphql(`query GetUser { user { id ...UserFields } }`);\n",
+		"src/UserCopy.tsx": "import { graphql } from './gql';\n\nconst UserFields = graphql(`fragment UserFields on User { name }`);\n" +
+			"const GetUser = graphql(`query GetUser { user { id ...UserFields } }`);\n",
+		"codegen.yml": "schema: schema.graphql\ndocuments: src/**/*.tsx\ngenerates:\n  src/gql/:\n    preset: client\n" +
+			"  broken.ts:\n    preset: client\n",
+	})
+
+	e := ExecutionContext{}
+	e.SetProjects(findTestProjects(t, dir))
+	e.LoadSchemas()
+
+	result := e.Execute()
+	if len(result.Errors) != 1 || result.Errors[0].Destination != "broken.ts" {
+		t.Errorf("Execute() errors = %v, expected an error for the broken.ts preset", result.Errors)
+	}
+
+	var destinations []string
+	for _, destination := range result.Destinations {
+		destinations = append(destinations, destination.Destination)
+	}
+	expected := []string{"src/gql/fragment-masking.ts", "src/gql/gql.ts", "src/gql/graphql.ts", "src/gql/index.ts"}
+	if !slices.Equal(destinations, expected) {
+		t.Fatalf("Execute() destinations = %v, expected %v", destinations, expected)
+	}
+
+	contents := map[string]string{
+		"graphql.ts": "export type GetUserQuery = { __typename?: 'Query', user?: ({ __typename?: 'User', id: Scalars['ID']['output'] } & " +
+			"{ ' $fragmentRefs'?: { 'UserFieldsFragment': UserFieldsFragment } }) | null };",
+		"gql.ts": `export function graphql(source: "query GetUser { user { id ...UserFields } }"): ` +
+			`(typeof documents)["query GetUser { user { id ...UserFields } }"];`,
+		"fragment-masking.ts": "export function useFragment<TType>(",
+		"index.ts":            "export * from \"./fragment-masking\";\nexport * from \"./gql\";\n",
+	}
+	for file, expectedContent := range contents {
+		content, err := os.ReadFile(filepath.Join(dir, "src", "gql", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(content), expectedContent) {
+			t.Errorf("%s does not contain %q:\n%s", file, expectedContent, content)
+		}
+	}
+
+	// the documents copied to another file are written once
+	graphql, _ := os.ReadFile(filepath.Join(dir, "src", "gql", "graphql.ts"))
+	for _, name := range []string{"export type GetUserQuery ", "export type UserFieldsFragment ", "export const GetUserDocument ", "export const UserFieldsFragmentDoc "} {
+		if count := strings.Count(string(graphql), name); count != 1 {
+			t.Errorf("graphql.ts contains %q %d times, expected once", name, count)
+		}
+	}
+}
+
+// TestExecuteHeaderHash tests that the hash in the header changes when only a document changes
+func TestExecuteHeaderHash(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"schema.graphql":        "type Query { hello: String, goodbye: String }",
+		"queries/hello.graphql": "query Hello { hello }",
+		"codegen.yml": "schema: schema.graphql\ndocuments: queries/*.graphql\nheader: '{hash}'\n" +
+			"generates:\n  types.ts:\n    plugins: [typescript-operations]\n",
+	})
+
+	e := ExecutionContext{}
+	e.SetProjects(findTestProjects(t, dir))
+	e.LoadSchemas()
+
+	readHeader := func() string {
+		t.Helper()
+
+		if result := e.Execute(); len(result.Errors) > 0 {
+			t.Fatal(result.Errors)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, "types.ts"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return strings.SplitN(string(content), "\n", 2)[0]
+	}
+
+	before := readHeader()
+
+	if err := os.WriteFile(filepath.Join(dir, "queries", "hello.graphql"), []byte("query Hello { goodbye }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if after := readHeader(); after == before {
+		t.Errorf("header = %q after changing a document, expected it to change", after)
+	}
+}
+
+// TestExecuteHeaderHashRoots tests that the same project generates the same header wherever it is checked out
+func TestExecuteHeaderHashRoots(t *testing.T) {
+	files := map[string]string{
+		"schema.graphql":        "type Query { hello: String }",
+		"queries/hello.graphql": "query Hello { hello }",
+		"codegen.yml": "schema: schema.graphql\ndocuments: queries/*.graphql\nheader: '{hash}'\n" +
+			"generates:\n  types.ts:\n    plugins: [typescript, typescript-operations]\n",
+	}
+
+	nestedFiles := make(map[string]string)
+	for name, content := range files {
+		nestedFiles[filepath.Join("other", "checkout", name)] = content
+	}
+
+	var outputs []string
+	for _, dir := range []string{writeTestFiles(t, files), filepath.Join(writeTestFiles(t, nestedFiles), "other", "checkout")} {
+		e := ExecutionContext{}
+		e.SetProjects(findTestProjects(t, dir))
+		e.LoadSchemas()
+		if result := e.Execute(); len(result.Errors) > 0 {
+			t.Fatal(result.Errors)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, "types.ts"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, string(content))
+	}
+
+	if outputs[0] != outputs[1] {
+		t.Errorf("output differs between checkouts:\n%s\n%s", strings.SplitN(outputs[0], "\n", 2)[0], strings.SplitN(outputs[1], "\n", 2)[0])
+	}
+}
+
+// TestExecuteValidationErrors tests that every validation error is reported in the original file, and that they can
+// be ignored
+func TestExecuteValidationErrors(t *testing.T) {
+	files := map[string]string{
+		"schema.graphql": "type Query { hello: String }",
+		"src/App.tsx": "import { gql } from '@apollo/client';\n\nconst A = gql`\n  query A {\n    goodbye\n  }\n`;\n\n" +
+			"const B = gql`query B($id: ID!) { hello(id: $id) }`;\n",
+	}
+
+	tests := []struct {
+		name    string
+		ignored bool
+	}{
+		{"Errors", false},
+		{"Ignored", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files["codegen.yml"] = fmt.Sprintf("schema: schema.graphql\ndocuments: src/*.tsx\nignoreValidationErrors: %t\n"+
+				"generates:\n  types.ts:\n    plugins: [typescript-operations]\n", tt.ignored)
+			dir := writeTestFiles(t, files)
+
+			e := ExecutionContext{}
+			e.SetProjects(findTestProjects(t, dir))
+			e.LoadSchemas()
+
+			result := e.Execute()
+
+			reported := result.Errors
+			if tt.ignored {
+				if len(result.Errors) > 0 || len(result.Destinations) != 1 {
+					t.Fatalf("Execute() = %v, %v, expected types.ts to be generated", result.Errors, result.Destinations)
+				}
+				reported = result.Warnings
+			}
+
+			var lines []int
+			for _, taskError := range reported {
+				var diagnostic *Diagnostic
+				if errors.As(taskError, &diagnostic) && strings.HasSuffix(diagnostic.File, "App.tsx") {
+					lines = append(lines, diagnostic.Line)
+				}
+			}
+
+			if !slices.Equal(lines, []int{5, 9}) {
+				t.Errorf("Execute() reported errors on lines %v of App.tsx, expected 5 and 9: %v", lines, reported)
+			}
+		})
+	}
+}
+
+// TestSchemaKeyHeaders tests that a URL requested with other headers is keyed apart, and files are not
+func TestSchemaKeyHeaders(t *testing.T) {
+	url := "https://example.com/graphql"
+	withHeaders := func(headers map[string]string) string {
+		project := Project{Schemas: []string{url, "/schema.graphql"}, SchemaHeaders: map[string]map[string]string{url: headers}}
+		return project.SchemaKey()
+	}
+
+	if withHeaders(nil) == withHeaders(map[string]string{"Authorization": "Bearer a"}) {
+		t.Errorf("SchemaKey() is the same with and without headers")
+	}
+	if withHeaders(map[string]string{"Authorization": "Bearer a"}) == withHeaders(map[string]string{"Authorization": "Bearer b"}) {
+		t.Errorf("SchemaKey() is the same for other headers")
+	}
+	if key := withHeaders(map[string]string{"Authorization": "Bearer a"}); strings.Contains(key, "Bearer") {
+		t.Errorf("SchemaKey() = %q, expected headers to be hashed", key)
+	}
+
+	file := Project{Schemas: []string{"/schema.graphql"}, SchemaHeaders: map[string]map[string]string{"/schema.graphql": {"a": "b"}}}
+	if file.SchemaKey() != "/schema.graphql" {
+		t.Errorf("SchemaKey() = %q, expected headers of files to be ignored", file.SchemaKey())
+	}
+}
+
+// TestSchemaKeyMatchesAcrossProjects tests that projects pointing at the same schema files share a schema key
+func TestSchemaKeyMatchesAcrossProjects(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"schemas/a.graphql": "",
+		"schemas/b.graphql": "",
+	})
+
+	first, err := ExpandSchemas(filepath.Join(dir, "packages", "first"), []string{"../../schemas/*.graphql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := ExpandSchemas(dir, []string{"schemas/b.graphql", "schemas/a.graphql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstProject := Project{Schemas: first}
+	secondProject := Project{Schemas: second}
+
+	if firstProject.SchemaKey() != secondProject.SchemaKey() {
+		t.Errorf("SchemaKey() = %q and %q, expected them to match", firstProject.SchemaKey(), secondProject.SchemaKey())
+	}
+}
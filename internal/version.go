@@ -0,0 +1,6 @@
+package internal
+
+/*
+Version of faster-graphql-codegen, set at build time with -ldflags "-X github.com/simse/faster-graphql-codegen/internal.Version=..."
+*/
+var Version = "dev"
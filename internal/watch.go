@@ -0,0 +1,304 @@
+package internal
+
+import (
+	"errors"
+	"github.com/fsnotify/fsnotify"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+DefaultDebounce is how long Watcher waits for more changes before regenerating, editors often save in bursts
+*/
+const DefaultDebounce = 100 * time.Millisecond
+
+/*
+Watcher keeps an ExecutionContext resident and regenerates the projects affected by changes to their config files,
+schemas and documents
+*/
+type Watcher struct {
+	Context  *ExecutionContext
+	Debounce time.Duration
+
+	watcher *fsnotify.Watcher
+	files   map[string]bool
+	dirs    map[string]bool
+	// globDirs are the directories glob patterns search, directories created in them are watched as well
+	globDirs []string
+}
+
+/*
+WatchResult describes a regeneration caused by changed files
+*/
+type WatchResult struct {
+	ChangedFiles  []string
+	SchemasLoaded int
+	// Errors holds the errors of projects that could not be refreshed or loaded, generation errors are in Result
+	Errors []*TaskError
+	Result ExecuteResult
+}
+
+/*
+NewWatcher starts watching the files of every project in the execution context
+*/
+func NewWatcher(e *ExecutionContext) (*Watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		Context:  e,
+		Debounce: DefaultDebounce,
+		watcher:  fsWatcher,
+		dirs:     make(map[string]bool),
+	}
+
+	if err := w.updateFiles(); err != nil {
+		_ = fsWatcher.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+/*
+Files returns every file that is being watched
+*/
+func (w *Watcher) Files() []string {
+	files := make([]string, 0, len(w.files))
+	for file := range w.files {
+		files = append(files, file)
+	}
+	slices.Sort(files)
+
+	return files
+}
+
+/*
+Run blocks until the watcher is closed, calling onChange after every regeneration. Besides the watched files, new
+files matching the schema or document patterns of a project are picked up. Errors of the underlying watcher are
+logged, they do not stop watching.
+*/
+func (w *Watcher) Run(onChange func(WatchResult)) error {
+	var changed []string
+	var timer <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			file := filepath.Clean(event.Name)
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			if !w.files[file] {
+				if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+					continue
+				}
+
+				if event.Has(fsnotify.Create) {
+					w.watchNewDir(file)
+				}
+
+				if !w.matchesProject(file) {
+					continue
+				}
+			}
+
+			if !slices.Contains(changed, file) {
+				changed = append(changed, file)
+			}
+			timer = time.After(w.Debounce)
+		case <-timer:
+			timer = nil
+
+			result := w.HandleChanges(changed)
+			changed = nil
+
+			// config changes can add schemas and documents
+			if err := w.updateFiles(); err != nil {
+				return err
+			}
+
+			onChange(result)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			// events may have been dropped, the next change regenerates again
+			slog.Warn("error watching files", "error", err)
+		}
+	}
+}
+
+/*
+Close stops watching, Run returns once it is closed
+*/
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
+
+/*
+HandleChanges reloads the schemas that depend on the changed files and runs the generation tasks of the projects
+that use them, or whose config file or documents changed
+*/
+func (w *Watcher) HandleChanges(changed []string) WatchResult {
+	e := w.Context
+	result := WatchResult{
+		ChangedFiles: changed,
+	}
+
+	var schemaKeys []string
+	var affectedRoots []string
+	for i := range e.Projects {
+		project := &e.Projects[i]
+
+		if slices.Contains(changed, filepath.Join(project.RootDir, project.ConfigFile)) {
+			// schemas of a project can only change with its config
+			if err := project.Refresh(); err != nil {
+				result.Errors = append(result.Errors, &TaskError{
+					ProjectRoot: project.RootDir,
+					ConfigFile:  project.ConfigFile,
+					Err:         err,
+				})
+				continue
+			}
+
+			schemaKeys = append(schemaKeys, project.SchemaKey())
+			affectedRoots = append(affectedRoots, project.RootDir)
+			continue
+		}
+
+		schemaChanged := false
+		for _, file := range changed {
+			// files are matched against the patterns, since they may have been added or removed
+			matchesSchema, matchesDocument, err := project.MatchFile(file)
+			if err != nil || (!matchesSchema && !matchesDocument) {
+				continue
+			}
+
+			schemaChanged = schemaChanged || matchesSchema
+			affectedRoots = append(affectedRoots, project.RootDir)
+		}
+
+		if !schemaChanged {
+			continue
+		}
+
+		// schema files matched by a glob may have been added or removed
+		if err := project.Refresh(); err != nil {
+			result.Errors = append(result.Errors, &TaskError{
+				ProjectRoot: project.RootDir,
+				ConfigFile:  project.ConfigFile,
+				Err:         err,
+			})
+			continue
+		}
+		schemaKeys = append(schemaKeys, project.SchemaKey())
+	}
+
+	if len(schemaKeys) > 0 {
+		schemasLoaded, schemaErrors := e.ReloadSchemas(schemaKeys)
+		result.SchemasLoaded = schemasLoaded
+		result.Errors = append(result.Errors, schemaErrors...)
+	}
+
+	// projects sharing a reloaded schema are affected as well
+	var affectedProjects []Project
+	for _, project := range e.Projects {
+		if slices.Contains(affectedRoots, project.RootDir) || slices.Contains(schemaKeys, project.SchemaKey()) {
+			affectedProjects = append(affectedProjects, project)
+		}
+	}
+
+	result.Result = e.ExecuteProjects(affectedProjects)
+
+	return result
+}
+
+/*
+matchesProject checks if a file is matched by the schema or document patterns of any project
+*/
+func (w *Watcher) matchesProject(file string) bool {
+	for i := range w.Context.Projects {
+		matchesSchema, matchesDocument, _ := w.Context.Projects[i].MatchFile(file)
+		if matchesSchema || matchesDocument {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+watchNewDir watches a directory created in a directory glob patterns search, so files added to it are picked up
+*/
+func (w *Watcher) watchNewDir(dir string) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() || w.dirs[dir] {
+		return
+	}
+
+	for _, globDir := range w.globDirs {
+		if strings.HasPrefix(dir, globDir+string(filepath.Separator)) {
+			if err := w.watcher.Add(dir); err == nil {
+				w.dirs[dir] = true
+			}
+			return
+		}
+	}
+}
+
+/*
+updateFiles collects the watched files of every project, and watches the directories they are in. Directories are
+watched rather than files, since editors often save by replacing a file. The directories glob patterns search are
+watched too, so new files in them are picked up.
+*/
+func (w *Watcher) updateFiles() error {
+	w.files = make(map[string]bool)
+	w.globDirs = nil
+
+	for _, project := range w.Context.Projects {
+		// a broken config is still watched, so fixing it is picked up
+		w.files[filepath.Join(project.RootDir, project.ConfigFile)] = true
+
+		files, _ := project.WatchedFiles()
+		for _, file := range files {
+			w.files[file] = true
+		}
+
+		globDirs, _ := project.GlobDirs()
+		w.globDirs = append(w.globDirs, globDirs...)
+	}
+
+	dirs := slices.Clone(w.globDirs)
+	for file := range w.files {
+		dirs = append(dirs, filepath.Dir(file))
+	}
+
+	for _, dir := range dirs {
+		if w.dirs[dir] {
+			continue
+		}
+
+		// missing files are reported when they are read, not by the watcher
+		if err := w.watcher.Add(dir); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		w.dirs[dir] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestWatcher(t *testing.T, dir string) *Watcher {
+	t.Helper()
+
+	e := &ExecutionContext{}
+	e.SetProjects(findTestProjects(t, dir))
+	e.LoadSchemas()
+	e.Execute()
+
+	watcher, err := NewWatcher(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = watcher.Close() })
+
+	return watcher
+}
+
+func destinationRoots(result ExecuteResult) []string {
+	var roots []string
+	for _, destination := range result.Destinations {
+		roots = append(roots, filepath.Base(destination.ProjectRoot))
+	}
+
+	return roots
+}
+
+// TestWatcherHandleChanges tests that only projects depending on a changed file are regenerated
+func TestWatcherHandleChanges(t *testing.T) {
+	config := "schema: ../shared.graphql\ngenerates:\n  out.ts:\n    plugins: [typescript]\n"
+	dir := writeTestFiles(t, map[string]string{
+		"shared.graphql":   "type Query { hello: String }",
+		"a/codegen.yml":    config,
+		"b/codegen.yml":    config,
+		"c/schema.graphql": "type Query { hello: String }",
+		"c/codegen.yml":    "schema: schema.graphql\ngenerates:\n  out.ts:\n    plugins: [typescript]\n",
+	})
+	watcher := newTestWatcher(t, dir)
+
+	if files := watcher.Files(); !slices.Contains(files, filepath.Join(dir, "shared.graphql")) || slices.Contains(files, filepath.Join(dir, "a", "out.ts")) {
+		t.Errorf("Files() = %v, expected schemas without outputs", files)
+	}
+
+	sharedSchema := filepath.Join(dir, "shared.graphql")
+	if err := os.WriteFile(sharedSchema, []byte("type Query { hello: String, world: String }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := watcher.HandleChanges([]string{sharedSchema})
+	if roots := destinationRoots(result.Result); !slices.Equal(roots, []string{"a", "b"}) {
+		t.Errorf("schema change regenerated %v, expected [a b]", roots)
+	}
+	if result.SchemasLoaded != 1 {
+		t.Errorf("schema change loaded %d schemas, expected 1", result.SchemasLoaded)
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(dir, "a", "out.ts")); !strings.Contains(string(content), "world") {
+		t.Errorf("output of a was not regenerated from the changed schema")
+	}
+
+	configFile := filepath.Join(dir, "c", "codegen.yml")
+	result = watcher.HandleChanges([]string{configFile})
+	if roots := destinationRoots(result.Result); !slices.Equal(roots, []string{"c"}) {
+		t.Errorf("config change regenerated %v, expected [c]", roots)
+	}
+
+	if err := os.WriteFile(sharedSchema, []byte("type Query { hello: Unknown }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result = watcher.HandleChanges([]string{sharedSchema})
+	if len(result.Errors) != 1 || len(result.Result.Destinations) != 0 {
+		t.Errorf("invalid schema change = %+v, expected one error and no outputs", result)
+	}
+}
+
+// TestWatcherRun tests that changes on disk are picked up and debounced
+func TestWatcherRun(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"schema.graphql": "type Query { hello: String }",
+		"codegen.yml":    "schema: schema.graphql\ngenerates:\n  out.ts:\n    plugins: [typescript]\n",
+	})
+	watcher := newTestWatcher(t, dir)
+
+	results := make(chan WatchResult, 10)
+	go func() {
+		_ = watcher.Run(func(result WatchResult) {
+			results <- result
+		})
+	}()
+
+	schemaFile := filepath.Join(dir, "schema.graphql")
+	for _, schema := range []string{"type Query { a: String }", "type Query { b: String }"} {
+		if err := os.WriteFile(schemaFile, []byte(schema), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case result := <-results:
+		if !slices.Equal(result.ChangedFiles, []string{schemaFile}) || len(result.Result.Destinations) != 1 {
+			t.Errorf("Run() result = %+v, expected one regenerated output", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not pick up the changed schema")
+	}
+
+	select {
+	case result := <-results:
+		t.Errorf("Run() regenerated again for %v, expected the writes to be debounced", result.ChangedFiles)
+	case <-time.After(3 * watcher.Debounce):
+	}
+}
+
+// TestWatcherRunNewFiles tests that files created after watching started are picked up when they match a pattern
+func TestWatcherRunNewFiles(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"schema/base.graphql": "type Query { hello: String }",
+		"src/hello.graphql":   "query Hello { hello }",
+		"codegen.yml": "schema: schema/*.graphql\ndocuments: src/**/*.graphql\n" +
+			"generates:\n  out.ts:\n    plugins: [typescript, typescript-operations]\n",
+	})
+	watcher := newTestWatcher(t, dir)
+
+	results := make(chan WatchResult, 10)
+	go func() {
+		_ = watcher.Run(func(result WatchResult) {
+			results <- result
+		})
+	}()
+
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "Schema",
+			file:     "schema/world.graphql",
+			content:  "extend type Query { world: String }",
+			expected: "world?: Maybe<Scalars['String']['output']>",
+		},
+		{
+			name:     "Document",
+			file:     "src/world.graphql",
+			content:  "query World { world }",
+			expected: "export type WorldQuery",
+		},
+		{
+			name:     "DocumentInNewDir",
+			file:     "src/nested/both.graphql",
+			content:  "query Both { hello world }",
+			expected: "export type BothQuery",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, tt.file)
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				t.Fatal(err)
+			}
+			// give the watcher a moment to watch a new directory before writing to it
+			time.Sleep(3 * watcher.Debounce)
+			if err := os.WriteFile(file, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case result := <-results:
+				if !slices.Contains(result.ChangedFiles, file) || len(result.Result.Destinations) != 1 {
+					t.Errorf("Run() result = %+v, expected the new file to regenerate the output", result)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Run() did not pick up the new file")
+			}
+
+			if content, _ := os.ReadFile(filepath.Join(dir, "out.ts")); !strings.Contains(string(content), tt.expected) {
+				t.Errorf("output does not contain %q:\n%s", tt.expected, content)
+			}
+		})
+	}
+}
@@ -2,36 +2,119 @@ package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
 	"github.com/briandowns/spinner"
 	"github.com/gookit/color"
 	"github.com/simse/faster-graphql-codegen/internal"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
+/*
+arguments are the flags and input folder the command is run with
+*/
+type arguments struct {
+	searchFolder string
+	noCache      bool
+	check        bool
+	diff         bool
+	watchFiles   bool
+}
+
+/*
+parseArgs parses the command line. Flags may come before or after the input folder, which is the current directory
+unless given, and more than one folder is an error. Errors are printed with the usage.
+*/
+func parseArgs(args []string) (arguments, error) {
+	parsed := arguments{searchFolder: "."}
+
+	flags := flag.NewFlagSet("faster-graphql-codegen", flag.ContinueOnError)
+	flags.BoolVar(&parsed.noCache, "no-cache", false, "do not read or write the schema cache")
+	flags.BoolVar(&parsed.check, "check", false, "check that generated files are up to date instead of writing them")
+	flags.BoolVar(&parsed.diff, "diff", false, "print a diff for every out of date file, implies --check")
+	flags.BoolVar(&parsed.watchFiles, "watch", false, "regenerate whenever a config file, schema or document changes")
+
+	// parsing stops at the first argument that is not a flag, so the flags after it are parsed again
+	var folders []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return parsed, err
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+
+		folders = append(folders, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+
+	if len(folders) > 1 {
+		// reported like the flag package reports invalid flags
+		err := fmt.Errorf("expected one input folder, got %s", strings.Join(folders, ", "))
+		fmt.Fprintln(flags.Output(), err)
+		flags.Usage()
+		return parsed, err
+	}
+	if len(folders) == 1 {
+		parsed.searchFolder = folders[0]
+	}
+
+	// a diff is only created in check mode, so asking for one means checking
+	if parsed.diff {
+		parsed.check = true
+	}
+
+	return parsed, nil
+}
+
 func main() {
 	timeStart := time.Now()
 
-	projects := findProjects()
+	args, err := parseArgs(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		os.Exit(2)
+	}
+	searchFolder := args.searchFolder
+
+	projects, projectLoadErrors := findProjects(searchFolder)
 
-	executionContext := internal.ExecutionContext{}
+	executionContext := internal.ExecutionContext{
+		Check: args.check,
+		Diff:  args.diff,
+	}
 	executionContext.SetProjects(projects)
 
-	loadSchemas(&executionContext)
-	execute(&executionContext, timeStart)
-}
+	if !args.noCache {
+		executionContext.SchemaCache = internal.NewSchemaCache(searchFolder)
+	}
 
-func findProjects() []internal.Project {
-	// get input folder
-	searchFolder := "."
-	argsWithoutProg := os.Args[1:]
+	schemasLoaded, schemaErrors := loadSchemas(&executionContext)
+	if schemasLoaded == 0 && !args.watchFiles {
+		os.Exit(1)
+	}
 
-	if len(argsWithoutProg) > 0 {
-		searchFolder = argsWithoutProg[0]
+	// config files that failed to load were reported when finding projects, they fail the run like task errors
+	succeeded := execute(&executionContext, timeStart, schemaErrors) && len(projectLoadErrors) == 0
+	if args.watchFiles {
+		watch(&executionContext)
 	}
 
+	if !succeeded {
+		os.Exit(1)
+	}
+}
+
+/*
+findProjects finds the projects in a folder and prints the config files that failed to load, it exits if the folder
+cannot be searched
+*/
+func findProjects(searchFolder string) ([]internal.Project, []internal.ProjectLoadError) {
 	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(os.Stderr))
 	s.Suffix = " Finding projects using codegen"
 
@@ -63,35 +146,231 @@ func findProjects() []internal.Project {
 		}
 	}
 
-	return projectSearchResult.Projects
+	return projectSearchResult.Projects, projectSearchResult.ProjectLoadErrors
 }
 
-func loadSchemas(e *internal.ExecutionContext) {
+func loadSchemas(e *internal.ExecutionContext) (int, []*internal.TaskError) {
 	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(os.Stderr))
 	s.Suffix = " Loading graphql schemas"
 
 	s.Start()
-	schemasLoaded := e.LoadSchemas()
+	schemasLoaded, taskErrors := e.LoadSchemas()
 
 	if schemasLoaded == 0 {
 		s.FinalMSG = errorString("No schemas loaded. Did any config files load?\n")
 		s.Stop()
-		os.Exit(1)
+		printTaskErrors(taskErrors)
+		return 0, taskErrors
 	}
 
-	s.FinalMSG = successString("Loaded %d unique schemas\n", schemasLoaded)
+	if len(taskErrors) > 0 {
+		s.FinalMSG = errorString("Loaded %d unique schemas, %d failed to load\n", schemasLoaded, len(taskErrors))
+	} else {
+		s.FinalMSG = successString("Loaded %d unique schemas\n", schemasLoaded)
+	}
 	s.Stop()
+
+	return schemasLoaded, taskErrors
 }
 
-func execute(e *internal.ExecutionContext, timeStart time.Time) {
+/*
+execute runs every codegen task and prints the results, it returns false if any task failed or any file is out of
+date in check mode
+*/
+func execute(e *internal.ExecutionContext, timeStart time.Time, schemaErrors []*internal.TaskError) bool {
 	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriter(os.Stderr))
 	s.Suffix = " Executing codegen tasks"
 
 	s.Start()
-	e.Execute()
+	result := e.Execute()
+	taskErrors := append(schemaErrors, result.Errors...)
 
-	s.FinalMSG = successString("Codegen completed in %s\n", time.Since(timeStart).String())
+	if len(taskErrors) > 0 {
+		s.FinalMSG = errorString("Codegen completed with %d errors in %s\n", len(taskErrors), time.Since(timeStart).String())
+	} else {
+		s.FinalMSG = successString("Codegen completed in %s\n", time.Since(timeStart).String())
+	}
 	s.Stop()
+
+	if e.Check {
+		printOutOfDate(result)
+	} else {
+		printDestinations(result)
+	}
+
+	printWarnings(result.Warnings)
+
+	if len(taskErrors) > 0 {
+		printTaskErrors(taskErrors)
+		return false
+	}
+
+	return !e.Check || len(result.OutOfDate()) == 0
+}
+
+/*
+watch regenerates the projects affected by every change to their files, until the process is stopped
+*/
+func watch(e *internal.ExecutionContext) {
+	watcher, err := internal.NewWatcher(e)
+	if err != nil {
+		fmt.Println(errorString("Could not watch files: %s", err.Error()))
+		os.Exit(1)
+	}
+	defer watcher.Close()
+
+	fmt.Println(successString("Watching %d files for changes", len(watcher.Files())))
+
+	err = watcher.Run(func(result internal.WatchResult) {
+		fmt.Println()
+		for _, file := range result.ChangedFiles {
+			color.Gray.Println("\t" + file + " changed")
+		}
+
+		taskErrors := append(result.Errors, result.Result.Errors...)
+		if len(taskErrors) > 0 {
+			fmt.Println(errorString("Regenerated with %d errors", len(taskErrors)))
+		} else {
+			fmt.Println(successString("Regenerated %d files", len(result.Result.Destinations)))
+		}
+
+		if e.Check {
+			printOutOfDate(result.Result)
+		} else {
+			printDestinations(result.Result)
+		}
+
+		printWarnings(result.Result.Warnings)
+		printTaskErrors(taskErrors)
+	})
+	if err != nil {
+		fmt.Println(errorString("Stopped watching files: %s", err.Error()))
+		os.Exit(1)
+	}
+}
+
+/*
+printOutOfDate lists every file that check mode found to be out of date, with its diff if one was created
+*/
+func printOutOfDate(result internal.ExecuteResult) {
+	outOfDate := result.OutOfDate()
+	if len(outOfDate) == 0 {
+		fmt.Println(successString("All %d generated files are up to date", len(result.Destinations)))
+		return
+	}
+
+	fmt.Println(errorString("%d of %d generated files are out of date", len(outOfDate), len(result.Destinations)))
+
+	for _, destination := range outOfDate {
+		color.Gray.Printf("\t%s ", filepath.Join(destination.ProjectRoot, destination.Destination))
+		fmt.Println("(" + string(destination.Status) + ")")
+
+		for _, line := range strings.Split(strings.TrimSuffix(destination.Diff, "\n"), "\n") {
+			switch {
+			case line == "":
+			case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+				color.Bold.Println(line)
+			case strings.HasPrefix(line, "+"):
+				color.Green.Println(line)
+			case strings.HasPrefix(line, "-"):
+				color.Red.Println(line)
+			case strings.HasPrefix(line, "@@"):
+				color.Cyan.Println(line)
+			default:
+				fmt.Println(line)
+			}
+		}
+	}
+}
+
+/*
+printDestinations lists every file that was written, followed by a count per status
+*/
+func printDestinations(result internal.ExecuteResult) {
+	for _, destination := range result.Destinations {
+		if destination.Status == internal.DestinationUnchanged {
+			continue
+		}
+
+		color.Gray.Printf("\t%s ", filepath.Join(destination.ProjectRoot, destination.Destination))
+		fmt.Println("(" + string(destination.Status) + ")")
+	}
+
+	fmt.Printf(
+		"\t%d created, %d updated, %d unchanged, %d skipped\n",
+		result.CountStatus(internal.DestinationCreated),
+		result.CountStatus(internal.DestinationUpdated),
+		result.CountStatus(internal.DestinationUnchanged),
+		result.CountStatus(internal.DestinationSkipped),
+	)
+}
+
+/*
+printWarnings prints the document validation errors that were ignored, they do not fail the run
+*/
+func printWarnings(warnings []*internal.TaskError) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	color.Yellow.Printf("! Ignored %d document validation errors\n", len(warnings))
+	printTaskErrors(warnings)
+}
+
+/*
+printTaskErrors prints task errors grouped by the config file of their project
+*/
+func printTaskErrors(taskErrors []*internal.TaskError) {
+	var configFiles []string
+	errorsByConfigFile := make(map[string][]*internal.TaskError)
+
+	for _, taskError := range taskErrors {
+		configFile := filepath.Join(taskError.ProjectRoot, taskError.ConfigFile)
+
+		if _, ok := errorsByConfigFile[configFile]; !ok {
+			configFiles = append(configFiles, configFile)
+		}
+		errorsByConfigFile[configFile] = append(errorsByConfigFile[configFile], taskError)
+	}
+
+	for _, configFile := range configFiles {
+		color.Gray.Println("\t" + configFile)
+
+		for _, taskError := range errorsByConfigFile[configFile] {
+			task := "schema"
+			if taskError.Documents {
+				task = "documents"
+			}
+			if taskError.Destination != "" {
+				task = taskError.Destination
+			}
+			if taskError.Plugin != "" {
+				task += " [" + taskError.Plugin + "]"
+			}
+
+			fmt.Println("\t↳ " + task + ": " + taskError.Err.Error())
+
+			var diagnostic *internal.Diagnostic
+			if errors.As(taskError.Err, &diagnostic) {
+				printCodeFrame(diagnostic)
+			}
+		}
+	}
+}
+
+func printCodeFrame(diagnostic *internal.Diagnostic) {
+	codeFrame := diagnostic.CodeFrame()
+	if codeFrame == "" {
+		return
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(codeFrame, "\n"), "\n") {
+		if strings.HasPrefix(line, ">") {
+			color.Red.Println("\t  " + line)
+		} else {
+			color.Gray.Println("\t  " + line)
+		}
+	}
 }
 
 func greenTick() string {
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+// TestParseArgs tests that flags are parsed before and after the input folder
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected arguments
+		wantErr  bool
+	}{
+		{
+			name:     "NoArguments",
+			expected: arguments{searchFolder: "."},
+		},
+		{
+			name:     "FlagsBeforeFolder",
+			args:     []string{"--check", "--no-cache", "packages"},
+			expected: arguments{searchFolder: "packages", check: true, noCache: true},
+		},
+		{
+			name:     "FlagsAfterFolder",
+			args:     []string{"packages", "--check", "--watch"},
+			expected: arguments{searchFolder: "packages", check: true, watchFiles: true},
+		},
+		{
+			name:     "DiffImpliesCheck",
+			args:     []string{".", "--diff"},
+			expected: arguments{searchFolder: ".", check: true, diff: true},
+		},
+		{
+			name:    "SeveralFolders",
+			args:    []string{"a", "--check", "b"},
+			wantErr: true,
+		},
+		{
+			name:    "UnknownFlag",
+			args:    []string{".", "--unknown"},
+			wantErr: true,
+		},
+	}
+
+	// usage is printed for invalid arguments
+	stderr := os.Stderr
+	os.Stderr, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	defer func() { os.Stderr = stderr }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && parsed != tt.expected {
+				t.Errorf("parseArgs() = %+v, expected %+v", parsed, tt.expected)
+			}
+		})
+	}
+}